@@ -13,10 +13,12 @@ import (
 	"resume-tailor/internal/config"
 	"resume-tailor/internal/db"
 	"resume-tailor/internal/httpapi"
+	"resume-tailor/internal/idempotency"
 	"resume-tailor/internal/jobs"
 	"resume-tailor/internal/resumes"
 	"resume-tailor/internal/runreports"
 	"resume-tailor/internal/runs"
+	"resume-tailor/internal/usage"
 )
 
 func main() {
@@ -44,7 +46,21 @@ func main() {
 	runreportsRepo := runreports.NewRepo(pool)
 	runreportsSvc := runreports.NewService(runreportsRepo)
 
-	router := httpapi.NewRouter(authSvc, runsSvc, resumesSvc, runreportsSvc)
+	usageSvc := usage.NewService(usage.NewRepo(pool), usage.NewPricing(cfg.LLMPrice), usage.Budgets{
+		UserMonthlyUSD:       cfg.LLMUserMonthlyBudgetUSD,
+		DeploymentMonthlyUSD: cfg.LLMMonthlyBudgetUSD,
+	})
+
+	idemRepo := idempotency.NewRepo(pool, cfg.IdempotencyKeyTTL)
+	if n, err := idemRepo.DeleteExpired(ctx); err != nil {
+		slog.Warn("failed to delete expired idempotency keys", "error", err)
+	} else if n > 0 {
+		slog.Info("deleted expired idempotency keys", "count", n)
+	}
+
+	adminSvc := jobs.NewAdminService(jobsRepo)
+
+	router := httpapi.NewRouter(authSvc, runsSvc, resumesSvc, runreportsSvc, usageSvc, idemRepo, adminSvc, cfg.AdminToken)
 
 	srv := &http.Server{
 		Addr:    cfg.HTTPAddr,
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Fixture is one evaluation case: a directory holding resume.txt, job.txt
+// and expected.json.
+type Fixture struct {
+	Name     string
+	Resume   string
+	Job      string
+	Expected Expected
+}
+
+// Expected lists what a good report must mention and what the scoring
+// stage should find. Skills are taxonomy names, compared case-insensitively.
+type Expected struct {
+	// MissingSkills must appear in the change plan
+	MissingSkills []string `json:"missing_skills"`
+	// ExperienceGaps are skills (or "experience") the posting asks more years of
+	ExperienceGaps []string `json:"experience_gaps,omitempty"`
+	SeniorityMet   *bool    `json:"seniority_met,omitempty"`
+}
+
+func loadFixtures(dir string) ([]Fixture, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []Fixture
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		f, err := loadFixture(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("fixture %s: %w", e.Name(), err)
+		}
+		out = append(out, f)
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("no fixtures in %s", dir)
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+func loadFixture(dir string) (Fixture, error) {
+	f := Fixture{Name: filepath.Base(dir)}
+
+	resume, err := os.ReadFile(filepath.Join(dir, "resume.txt"))
+	if err != nil {
+		return Fixture{}, err
+	}
+	job, err := os.ReadFile(filepath.Join(dir, "job.txt"))
+	if err != nil {
+		return Fixture{}, err
+	}
+	f.Resume, f.Job = string(resume), string(job)
+
+	expected, err := os.ReadFile(filepath.Join(dir, "expected.json"))
+	if err != nil {
+		return Fixture{}, err
+	}
+	if err := json.Unmarshal(expected, &f.Expected); err != nil {
+		return Fixture{}, fmt.Errorf("expected.json: %w", err)
+	}
+	return f, nil
+}
@@ -0,0 +1,251 @@
+// Command eval runs fixture cases through the scoring and report pipeline
+// with two LLM configurations and writes a comparison of their metrics.
+//
+//	go run ./cmd/eval -a provider=fake,prompt=v1 -b provider=fake,prompt=v2
+//
+// Each configuration is a comma-separated list of provider, model, prompt
+// and base_url. API keys are read from LLM_API_KEY or the provider's usual
+// variable (OPENAI_API_KEY, ANTHROPIC_API_KEY).
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"resume-tailor/internal/ai"
+	"resume-tailor/internal/scoring"
+	"resume-tailor/internal/scoring/taxonomy"
+)
+
+func main() {
+	fixturesDir := flag.String("fixtures", "eval/fixtures", "directory of fixture cases")
+	runs := flag.Int("runs", 3, "runs per fixture, used for score stability")
+	specA := flag.String("a", "provider=fake", "baseline configuration")
+	specB := flag.String("b", "", "candidate configuration (optional)")
+	out := flag.String("out", "", "write the report here (.json for JSON, otherwise Markdown); default stdout")
+	timeout := flag.Duration("timeout", 2*time.Minute, "timeout per LLM call")
+	flag.Parse()
+
+	if err := run(*fixturesDir, *runs, *specA, *specB, *out, *timeout); err != nil {
+		slog.Error("eval failed", "error", err)
+		os.Exit(1)
+	}
+}
+
+func run(fixturesDir string, runs int, specA, specB, out string, timeout time.Duration) error {
+	if runs < 1 {
+		return fmt.Errorf("-runs must be at least 1")
+	}
+
+	fixtures, err := loadFixtures(fixturesDir)
+	if err != nil {
+		return err
+	}
+
+	tax, err := taxonomy.Load(os.Getenv("SKILLS_TAXONOMY_PATH"))
+	if err != nil {
+		return fmt.Errorf("load skills taxonomy: %w", err)
+	}
+	scorer := scoring.NewScorer(tax)
+
+	prompts, err := ai.LoadPrompts()
+	if err != nil {
+		return err
+	}
+
+	specs := []string{specA}
+	if specB != "" {
+		specs = append(specs, specB)
+	}
+
+	var results []Result
+	for _, spec := range specs {
+		cfg, err := parseSpec(spec)
+		if err != nil {
+			return err
+		}
+		res, err := evaluate(cfg, prompts, scorer, fixtures, runs, timeout)
+		if err != nil {
+			return fmt.Errorf("%s: %w", spec, err)
+		}
+		results = append(results, res)
+	}
+
+	var w io.Writer = os.Stdout
+	if out != "" {
+		f, err := os.Create(out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if strings.EqualFold(filepath.Ext(out), ".json") {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	}
+	return writeMarkdown(w, results)
+}
+
+// Config is one side of the comparison.
+type Config struct {
+	Provider string `json:"provider"`
+	Model    string `json:"model"`
+	Prompt   string `json:"prompt"`
+	BaseURL  string `json:"base_url,omitempty"`
+}
+
+func (c Config) String() string {
+	return fmt.Sprintf("%s/%s %s", c.Provider, c.Model, c.Prompt)
+}
+
+func parseSpec(spec string) (Config, error) {
+	var c Config
+	for _, part := range strings.Split(spec, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return Config{}, fmt.Errorf("bad config %q: want key=value", part)
+		}
+		switch key {
+		case "provider":
+			c.Provider = value
+		case "model":
+			c.Model = value
+		case "prompt":
+			c.Prompt = value
+		case "base_url":
+			c.BaseURL = value
+		default:
+			return Config{}, fmt.Errorf("bad config %q: unknown key %q", spec, key)
+		}
+	}
+
+	if c.Provider == "" {
+		c.Provider = ai.ProviderFake
+	}
+	if c.Model == "" {
+		switch c.Provider {
+		case ai.ProviderOpenAI:
+			c.Model = "gpt-4o-mini"
+		case ai.ProviderAnthropic:
+			c.Model = "claude-3-5-haiku-latest"
+		case ai.ProviderFake:
+			c.Model = ai.ProviderFake
+		}
+	}
+	return c, nil
+}
+
+func apiKey(provider string) string {
+	if key := os.Getenv("LLM_API_KEY"); key != "" {
+		return key
+	}
+	switch provider {
+	case ai.ProviderOpenAI:
+		return os.Getenv("OPENAI_API_KEY")
+	case ai.ProviderAnthropic:
+		return os.Getenv("ANTHROPIC_API_KEY")
+	}
+	return ""
+}
+
+// meteredProvider counts calls and tokens of the wrapped provider.
+type meteredProvider struct {
+	ai.Provider
+
+	mu    sync.Mutex
+	usage Usage
+}
+
+// Usage is what one GenerateRunReport call cost.
+type Usage struct {
+	Calls        int
+	InputTokens  int
+	OutputTokens int
+}
+
+func (m *meteredProvider) Complete(ctx context.Context, req ai.CompletionRequest) (ai.Completion, error) {
+	resp, err := m.Provider.Complete(ctx, req)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.usage.Calls++
+	m.usage.InputTokens += resp.InputTokens
+	m.usage.OutputTokens += resp.OutputTokens
+	return resp, err
+}
+
+func (m *meteredProvider) take() Usage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	u := m.usage
+	m.usage = Usage{}
+	return u
+}
+
+func evaluate(cfg Config, prompts *ai.PromptRegistry, scorer *scoring.Scorer, fixtures []Fixture, runs int, timeout time.Duration) (Result, error) {
+	provider, err := ai.NewProvider(ai.ProviderConfig{
+		Name:    cfg.Provider,
+		APIKey:  apiKey(cfg.Provider),
+		BaseURL: cfg.BaseURL,
+		Model:   cfg.Model,
+	})
+	if err != nil {
+		return Result{}, err
+	}
+	prompt, err := prompts.Get(ai.PromptRunReport, cfg.Prompt)
+	if err != nil {
+		return Result{}, err
+	}
+	cfg.Prompt = prompt.Version
+
+	metered := &meteredProvider{Provider: provider}
+	client := ai.NewClient(metered, prompt)
+
+	// Fixed clock so "Present" resolves the same way on every run
+	now := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	res := Result{Config: cfg, SchemaVersion: prompt.SchemaVersion}
+	for _, f := range fixtures {
+		signals, err := scorer.Compute(f.Resume, f.Job, now)
+		if err != nil {
+			return Result{}, fmt.Errorf("fixture %s: %w", f.Name, err)
+		}
+
+		c := Case{Name: f.Name, SignalChecks: checkSignals(f.Expected, &signals)}
+		for i := 0; i < runs; i++ {
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			start := time.Now()
+			report, err := client.GenerateRunReport(ctx, f.Resume, f.Job, &signals)
+			latency := time.Since(start)
+			cancel()
+
+			usage := metered.take()
+			r := Run{Latency: latency, Usage: usage}
+			if err != nil {
+				r.Error = err.Error()
+			} else {
+				r.Valid = true
+				r.FirstAttemptValid = usage.Calls == 1
+				r.Score, r.Recall = scoreAndRecall(report, f.Expected.MissingSkills)
+			}
+			slog.Info("eval run", "config", cfg.String(), "fixture", f.Name, "run", i+1, "valid", r.Valid, "latency", latency)
+			c.Runs = append(c.Runs, r)
+		}
+		res.Cases = append(res.Cases, c)
+	}
+
+	res.summarize()
+	return res, nil
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"resume-tailor/internal/ai"
+	"resume-tailor/internal/scoring"
+	"resume-tailor/internal/scoring/taxonomy"
+)
+
+// TestEvaluateFakeProvider runs the fixtures through the fake provider,
+// which echoes the missing must-have terms, so every prompt version should
+// recall some of the expected missing skills.
+func TestEvaluateFakeProvider(t *testing.T) {
+	fixtures, err := loadFixtures("../../eval/fixtures")
+	if err != nil {
+		t.Fatalf("loadFixtures: %v", err)
+	}
+	tax, err := taxonomy.Default()
+	if err != nil {
+		t.Fatalf("taxonomy: %v", err)
+	}
+	prompts, err := ai.LoadPrompts()
+	if err != nil {
+		t.Fatalf("LoadPrompts: %v", err)
+	}
+
+	for _, prompt := range []string{"v1", "v2"} {
+		cfg, err := parseSpec("provider=fake,prompt=" + prompt)
+		if err != nil {
+			t.Fatalf("parseSpec: %v", err)
+		}
+		res, err := evaluate(cfg, prompts, scoring.NewScorer(tax), fixtures, 1, time.Minute)
+		if err != nil {
+			t.Fatalf("%s: evaluate: %v", prompt, err)
+		}
+
+		s := res.Summary
+		if s.ValidRate != 1 {
+			t.Errorf("%s: valid rate %v, want 1", prompt, s.ValidRate)
+		}
+		if s.MissingSkillRecall == 0 {
+			t.Errorf("%s: missing-skill recall is 0", prompt)
+		}
+		for _, c := range res.Cases {
+			for _, chk := range c.SignalChecks {
+				if !chk.Pass {
+					t.Errorf("%s/%s: signal check %q failed", prompt, c.Name, chk.Check)
+				}
+			}
+		}
+	}
+}
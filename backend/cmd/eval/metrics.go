@@ -0,0 +1,311 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"resume-tailor/internal/ai"
+	"resume-tailor/internal/scoring"
+)
+
+// Run is one GenerateRunReport call on one fixture.
+type Run struct {
+	Valid             bool          `json:"valid"`
+	FirstAttemptValid bool          `json:"first_attempt_valid"`
+	Score             float64       `json:"score"`
+	Recall            float64       `json:"recall"`
+	Latency           time.Duration `json:"latency"`
+	Usage             Usage         `json:"usage"`
+	Error             string        `json:"error,omitempty"`
+}
+
+// Case holds every run of one fixture.
+type Case struct {
+	Name         string        `json:"name"`
+	SignalChecks []SignalCheck `json:"signal_checks"`
+	Runs         []Run         `json:"runs"`
+}
+
+// SignalCheck compares one expectation with the scoring stage output.
+type SignalCheck struct {
+	Check string `json:"check"`
+	Pass  bool   `json:"pass"`
+}
+
+// Summary aggregates all runs of a configuration.
+type Summary struct {
+	Runs               int           `json:"runs"`
+	ValidRate          float64       `json:"valid_rate"`
+	FirstAttemptRate   float64       `json:"first_attempt_valid_rate"`
+	MeanScore          float64       `json:"mean_score"`
+	ScoreStdDev        float64       `json:"score_stddev"`
+	MissingSkillRecall float64       `json:"missing_skill_recall"`
+	LatencyMean        time.Duration `json:"latency_mean"`
+	LatencyP95         time.Duration `json:"latency_p95"`
+	InputTokensMean    float64       `json:"input_tokens_mean"`
+	OutputTokensMean   float64       `json:"output_tokens_mean"`
+	SignalChecks       string        `json:"signal_checks"`
+}
+
+// Result is the evaluation of one configuration.
+type Result struct {
+	Config        Config  `json:"config"`
+	SchemaVersion int     `json:"schema_version"`
+	Summary       Summary `json:"summary"`
+	Cases         []Case  `json:"cases"`
+}
+
+func (r *Result) summarize() {
+	var (
+		s                         Summary
+		valid, first              int
+		scores, recalls           []float64
+		stddevs                   []float64
+		latencies                 []time.Duration
+		inTokens, outTokens       int
+		checksPassed, checksTotal int
+	)
+
+	for _, c := range r.Cases {
+		var caseScores []float64
+		for _, run := range c.Runs {
+			s.Runs++
+			latencies = append(latencies, run.Latency)
+			inTokens += run.Usage.InputTokens
+			outTokens += run.Usage.OutputTokens
+			if !run.Valid {
+				continue
+			}
+			valid++
+			if run.FirstAttemptValid {
+				first++
+			}
+			caseScores = append(caseScores, run.Score)
+			recalls = append(recalls, run.Recall)
+		}
+		scores = append(scores, caseScores...)
+		if len(caseScores) > 1 {
+			stddevs = append(stddevs, stddev(caseScores))
+		}
+		for _, chk := range c.SignalChecks {
+			checksTotal++
+			if chk.Pass {
+				checksPassed++
+			}
+		}
+	}
+
+	if s.Runs > 0 {
+		s.ValidRate = float64(valid) / float64(s.Runs)
+		s.FirstAttemptRate = float64(first) / float64(s.Runs)
+		s.InputTokensMean = float64(inTokens) / float64(s.Runs)
+		s.OutputTokensMean = float64(outTokens) / float64(s.Runs)
+	}
+	s.MeanScore = mean(scores)
+	s.ScoreStdDev = mean(stddevs)
+	s.MissingSkillRecall = mean(recalls)
+	s.LatencyMean, s.LatencyP95 = latencyStats(latencies)
+	s.SignalChecks = fmt.Sprintf("%d/%d", checksPassed, checksTotal)
+	r.Summary = s
+}
+
+// scoreAndRecall reads the overall score (present in every schema version)
+// and the share of expected missing skills the change plan mentions.
+func scoreAndRecall(report ai.Report, missing []string) (float64, float64) {
+	var ats struct {
+		Score float64 `json:"score"`
+	}
+	_ = json.Unmarshal(report.ATSReport, &ats)
+
+	if len(missing) == 0 {
+		return ats.Score, 1
+	}
+	plan := string(report.ChangePlan)
+	found := 0
+	for _, skill := range missing {
+		if mentions(plan, skill) {
+			found++
+		}
+	}
+	return ats.Score, float64(found) / float64(len(missing))
+}
+
+func mentions(text, term string) bool {
+	re := regexp.MustCompile(`(?i)(?:^|[^\pL\pN])` + regexp.QuoteMeta(term) + `(?:$|[^\pL\pN+#])`)
+	return re.MatchString(text)
+}
+
+// checkSignals verifies the deterministic scoring stage against the
+// fixture's expectations, so a failing case can be told apart from a
+// failing prompt.
+func checkSignals(exp Expected, signals *scoring.Signals) []SignalCheck {
+	var out []SignalCheck
+
+	missing := make(map[string]bool)
+	for _, s := range signals.BM25.Skills {
+		if !s.Matched {
+			missing[strings.ToLower(s.Name)] = true
+		}
+	}
+	for _, skill := range exp.MissingSkills {
+		out = append(out, SignalCheck{Check: "missing skill " + skill, Pass: missing[strings.ToLower(skill)]})
+	}
+
+	gaps := make(map[string]bool)
+	for _, g := range signals.Experience.Gaps {
+		name := strings.ToLower(g.Name)
+		if g.Skill == "" {
+			name = "experience"
+		}
+		gaps[name] = true
+	}
+	for _, skill := range exp.ExperienceGaps {
+		out = append(out, SignalCheck{Check: "experience gap " + skill, Pass: gaps[strings.ToLower(skill)]})
+	}
+
+	if exp.SeniorityMet != nil {
+		out = append(out, SignalCheck{
+			Check: fmt.Sprintf("seniority met = %t", *exp.SeniorityMet),
+			Pass:  signals.Experience.Seniority.Met == *exp.SeniorityMet,
+		})
+	}
+	return out
+}
+
+func writeMarkdown(w io.Writer, results []Result) error {
+	var b strings.Builder
+	b.WriteString("# Report evaluation\n\n")
+
+	header := "| Metric |"
+	sep := "|---|"
+	for _, r := range results {
+		header += fmt.Sprintf(" %s (schema v%d) |", r.Config, r.SchemaVersion)
+		sep += "---|"
+	}
+	if len(results) == 2 {
+		header += " Δ |"
+		sep += "---|"
+	}
+	b.WriteString(header + "\n" + sep + "\n")
+
+	rows := []struct {
+		name   string
+		value  func(Summary) float64
+		format func(float64) string
+	}{
+		{"Runs", func(s Summary) float64 { return float64(s.Runs) }, formatInt},
+		{"Schema valid", func(s Summary) float64 { return s.ValidRate }, formatPercent},
+		{"Valid on first attempt", func(s Summary) float64 { return s.FirstAttemptRate }, formatPercent},
+		{"Mean score", func(s Summary) float64 { return s.MeanScore }, formatFloat},
+		{"Score std dev (per fixture)", func(s Summary) float64 { return s.ScoreStdDev }, formatFloat},
+		{"Missing-skill recall", func(s Summary) float64 { return s.MissingSkillRecall }, formatPercent},
+		{"Latency mean (ms)", func(s Summary) float64 { return milliseconds(s.LatencyMean) }, formatMillis},
+		{"Latency p95 (ms)", func(s Summary) float64 { return milliseconds(s.LatencyP95) }, formatMillis},
+		{"Input tokens / run", func(s Summary) float64 { return s.InputTokensMean }, formatInt},
+		{"Output tokens / run", func(s Summary) float64 { return s.OutputTokensMean }, formatInt},
+	}
+	for _, row := range rows {
+		line := "| " + row.name + " |"
+		for _, r := range results {
+			line += " " + row.format(row.value(r.Summary)) + " |"
+		}
+		if len(results) == 2 {
+			d := row.value(results[1].Summary) - row.value(results[0].Summary)
+			line += " " + signed(row.format(d), d) + " |"
+		}
+		b.WriteString(line + "\n")
+	}
+
+	line := "| Signal checks |"
+	for _, r := range results {
+		line += " " + r.Summary.SignalChecks + " |"
+	}
+	if len(results) == 2 {
+		line += " |"
+	}
+	b.WriteString(line + "\n")
+
+	for _, r := range results {
+		fmt.Fprintf(&b, "\n## %s\n\n| Fixture | Valid | Scores | Recall | Failed signal checks |\n|---|---|---|---|---|\n", r.Config)
+		for _, c := range r.Cases {
+			valid := 0
+			var scores, recalls []string
+			for _, run := range c.Runs {
+				if run.Valid {
+					valid++
+					scores = append(scores, formatFloat(run.Score))
+					recalls = append(recalls, formatPercent(run.Recall))
+				}
+			}
+			var failed []string
+			for _, chk := range c.SignalChecks {
+				if !chk.Pass {
+					failed = append(failed, chk.Check)
+				}
+			}
+			fmt.Fprintf(&b, "| %s | %d/%d | %s | %s | %s |\n",
+				c.Name, valid, len(c.Runs), strings.Join(scores, ", "), strings.Join(recalls, ", "), strings.Join(failed, "; "))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func mean(xs []float64) float64 {
+	if len(xs) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, x := range xs {
+		sum += x
+	}
+	return sum / float64(len(xs))
+}
+
+func stddev(xs []float64) float64 {
+	m := mean(xs)
+	sum := 0.0
+	for _, x := range xs {
+		sum += (x - m) * (x - m)
+	}
+	return math.Sqrt(sum / float64(len(xs)))
+}
+
+func latencyStats(ds []time.Duration) (time.Duration, time.Duration) {
+	if len(ds) == 0 {
+		return 0, 0
+	}
+	sorted := make([]time.Duration, len(ds))
+	copy(sorted, ds)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var total time.Duration
+	for _, d := range sorted {
+		total += d
+	}
+	p95 := sorted[int(math.Ceil(0.95*float64(len(sorted))))-1]
+	return total / time.Duration(len(sorted)), p95
+}
+
+func formatInt(f float64) string     { return fmt.Sprintf("%.0f", f) }
+func formatFloat(f float64) string   { return fmt.Sprintf("%.3f", f) }
+func formatPercent(f float64) string { return fmt.Sprintf("%.0f%%", f*100) }
+func formatMillis(f float64) string  { return fmt.Sprintf("%.1f", f) }
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+func signed(s string, d float64) string {
+	if d > 0 {
+		return "+" + s
+	}
+	return s
+}
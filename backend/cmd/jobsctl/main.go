@@ -0,0 +1,191 @@
+// Command jobsctl inspects and repairs the dead-letter queue: jobs that
+// failed after their last attempt.
+//
+//	go run ./cmd/jobsctl dead -type process_run -error timeout
+//	go run ./cmd/jobsctl show <job-id>
+//	go run ./cmd/jobsctl requeue -type process_run -error "rate limit"
+//	go run ./cmd/jobsctl discard <job-id> <job-id>
+//
+// requeue and discard act on the job IDs given as arguments and/or every
+// dead job matching -type and -error; at least one selector is required.
+// It connects to DATABASE_URL directly.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"resume-tailor/internal/db"
+	"resume-tailor/internal/jobs"
+
+	"github.com/google/uuid"
+)
+
+const usage = `usage: jobsctl <command> [flags] [job-id...]
+
+commands:
+  dead      list dead jobs (-type, -error, -limit, -offset)
+  show      show a job and its attempt history
+  requeue   requeue dead jobs by ID and/or -type, -error
+  discard   delete dead jobs by ID and/or -type, -error
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	if err := run(context.Background(), os.Args[1], os.Args[2:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "jobsctl:", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, cmd string, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet(cmd, flag.ExitOnError)
+	jobType := fs.String("type", "", "only jobs of this type")
+	errorText := fs.String("error", "", "only jobs whose last error contains this text")
+	limit := fs.Int("limit", 50, "maximum jobs to list")
+	offset := fs.Int("offset", 0, "jobs to skip when listing")
+	asJSON := fs.Bool("json", false, "print JSON instead of a table")
+	fs.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	switch cmd {
+	case "dead", "show", "requeue", "discard":
+	default:
+		fs.Usage()
+		return fmt.Errorf("unknown command %q", cmd)
+	}
+
+	filter := jobs.DeadJobFilter{Type: *jobType, Error: *errorText}
+	for _, raw := range fs.Args() {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return fmt.Errorf("invalid job id %q", raw)
+		}
+		filter.IDs = append(filter.IDs, id)
+	}
+
+	pool, err := db.Connect(ctx, os.Getenv("DATABASE_URL"))
+	if err != nil {
+		return err
+	}
+	defer db.Close(pool)
+	admin := jobs.NewAdminService(jobs.NewRepo(pool))
+
+	switch cmd {
+	case "dead":
+		list, err := admin.ListDeadJobs(ctx, filter, *limit, *offset)
+		if err != nil {
+			return err
+		}
+		if *asJSON {
+			return writeJSON(out, list)
+		}
+		return writeJobs(out, list)
+
+	case "show":
+		if len(filter.IDs) != 1 {
+			return fmt.Errorf("show takes exactly one job id")
+		}
+		detail, err := admin.GetJob(ctx, filter.IDs[0])
+		if err != nil {
+			return err
+		}
+		if *asJSON {
+			return writeJSON(out, detail)
+		}
+		return writeDetail(out, detail)
+
+	case "requeue":
+		ids, err := admin.Requeue(ctx, filter)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "requeued %d job(s)\n", len(ids))
+		return writeIDs(out, ids)
+
+	default:
+		ids, err := admin.Discard(ctx, filter)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "discarded %d job(s)\n", len(ids))
+		return writeIDs(out, ids)
+	}
+}
+
+func writeJobs(out io.Writer, list []jobs.Job) error {
+	tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tTYPE\tATTEMPTS\tFAILED AT\tLAST ERROR")
+	for _, j := range list {
+		fmt.Fprintf(tw, "%s\t%s\t%d/%d\t%s\t%s\n", j.ID, j.Type, j.Attempts, j.MaxAttempts, j.UpdatedAt.Format(time.RFC3339), oneLine(deref(j.LastError), 80))
+	}
+	return tw.Flush()
+}
+
+func writeDetail(out io.Writer, d jobs.JobDetail) error {
+	j := d.Job
+	fmt.Fprintf(out, "job:       %s\n", j.ID)
+	fmt.Fprintf(out, "type:      %s\n", j.Type)
+	if j.RunID != nil {
+		fmt.Fprintf(out, "run:       %s\n", *j.RunID)
+	}
+	fmt.Fprintf(out, "status:    %s (%d/%d attempts)\n", j.Status, j.Attempts, j.MaxAttempts)
+	fmt.Fprintf(out, "payload:   %s\n", j.Payload)
+	fmt.Fprintf(out, "created:   %s\n", j.CreatedAt.Format(time.RFC3339))
+	if j.LastError != nil {
+		fmt.Fprintf(out, "error:     %s\n", *j.LastError)
+	}
+	fmt.Fprintln(out)
+
+	tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ATTEMPT\tWORKER\tSTARTED\tDURATION\tOUTCOME\tERROR")
+	for _, a := range d.Attempts {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\n", a.Attempt, a.WorkerID, a.StartedAt.Format(time.RFC3339), a.Duration().Round(time.Millisecond), a.Outcome, oneLine(deref(a.Error), 80))
+	}
+	return tw.Flush()
+}
+
+func writeIDs(out io.Writer, ids []uuid.UUID) error {
+	for _, id := range ids {
+		if _, err := fmt.Fprintln(out, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeJSON(out io.Writer, v any) error {
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// oneLine flattens s and cuts it to max runes for table output.
+func oneLine(s string, max int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if r := []rune(s); len(r) > max {
+		return string(r[:max-1]) + "…"
+	}
+	return s
+}
@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
 	"os"
 	"os/signal"
@@ -11,9 +13,13 @@ import (
 	"resume-tailor/internal/config"
 	"resume-tailor/internal/db"
 	"resume-tailor/internal/jobs"
+	"resume-tailor/internal/llmcache"
 	"resume-tailor/internal/resumes"
 	"resume-tailor/internal/runreports"
 	"resume-tailor/internal/runs"
+	"resume-tailor/internal/scoring"
+	"resume-tailor/internal/scoring/taxonomy"
+	"resume-tailor/internal/usage"
 
 	"github.com/google/uuid"
 )
@@ -43,21 +49,85 @@ func main() {
 	// Create adapter to avoid import cycle
 	runsRepo := &runsRepoAdapter{repo: runsRepoRaw}
 
-	// Initialize AI client (may be nil if API key is missing)
-	var aiClient *ai.Client
-	if cfg.OpenAIAPIKey != "" {
-		var err error
-		aiClient, err = ai.NewClientFromEnv(cfg.OpenAIAPIKey, cfg.OpenAIModel)
+	// Initialize report generator (nil if no LLM is configured)
+	var generator jobs.ReportGenerator
+	if cfg.LLMEnabled() {
+		provider, err := ai.NewProvider(ai.ProviderConfig{
+			Name:    cfg.LLMProvider,
+			APIKey:  cfg.LLMAPIKey,
+			BaseURL: cfg.LLMBaseURL,
+			Model:   cfg.LLMModel,
+		})
 		if err != nil {
-			slog.Error("failed to create AI client", "error", err)
+			slog.Error("failed to create LLM provider", "error", err)
 			os.Exit(1)
 		}
-		slog.Info("AI client initialized", "model", cfg.OpenAIModel)
+		prompts, err := ai.LoadPrompts()
+		if err != nil {
+			slog.Error("failed to load prompts", "error", err)
+			os.Exit(1)
+		}
+		prompt, err := prompts.Latest(ai.PromptRunReport, cfg.ReportSchemaVersion)
+		if cfg.PromptVersion != "" {
+			prompt, err = prompts.Get(ai.PromptRunReport, cfg.PromptVersion)
+			if err == nil && prompt.SchemaVersion != cfg.ReportSchemaVersion {
+				err = fmt.Errorf("prompt %s produces schema v%d, REPORT_SCHEMA_VERSION is %d", prompt.ID(), prompt.SchemaVersion, cfg.ReportSchemaVersion)
+			}
+		}
+		if err != nil {
+			slog.Error("failed to resolve prompt", "error", err)
+			os.Exit(1)
+		}
+		condense, err := prompts.Get(ai.PromptCondenseResume, "")
+		if err != nil {
+			slog.Error("failed to resolve prompt", "error", err)
+			os.Exit(1)
+		}
+		client := ai.NewClient(provider, prompt).WithCondensePrompt(condense)
+		if cfg.LLMContextTokens > 0 {
+			client.WithContextWindow(cfg.LLMContextTokens)
+		}
+		if cfg.LLMTemperature != nil {
+			client.WithTemperature(*cfg.LLMTemperature)
+		}
+		if cfg.LLMCacheTTL > 0 {
+			cache := llmcache.NewRepo(pool, cfg.LLMCacheTTL)
+			if n, err := cache.DeleteExpired(ctx); err != nil {
+				slog.Warn("failed to delete expired LLM cache entries", "error", err)
+			} else if n > 0 {
+				slog.Info("deleted expired LLM cache entries", "count", n)
+			}
+			client.WithCache(cache)
+		}
+		generator = client
+		slog.Info("LLM provider initialized", "provider", provider.Name(), "model", provider.Model(), "prompt", prompt.ID(), "cache_ttl", cfg.LLMCacheTTL)
+	} else if cfg.ReportMode == jobs.ReportModeLLM {
+		slog.Warn("no LLM API key set, worker will fail jobs that require AI", "provider", cfg.LLMProvider)
 	} else {
-		slog.Warn("OPENAI_API_KEY not set, worker will fail jobs that require AI")
+		slog.Warn("no LLM API key set, reports will be generated deterministically", "provider", cfg.LLMProvider)
+	}
+
+	// Load skills taxonomy used to normalize resume and job text before scoring
+	skills, err := taxonomy.Load(cfg.SkillsTaxonomyPath)
+	if err != nil {
+		slog.Error("failed to load skills taxonomy", "error", err)
+		os.Exit(1)
 	}
+	slog.Info("skills taxonomy loaded", "version", skills.Version, "skills", len(skills.Skills))
+	scorer := scoring.NewScorer(skills)
+
+	usageSvc := usage.NewService(usage.NewRepo(pool), usage.NewPricing(cfg.LLMPrice), usage.Budgets{
+		UserMonthlyUSD:       cfg.LLMUserMonthlyBudgetUSD,
+		DeploymentMonthlyUSD: cfg.LLMMonthlyBudgetUSD,
+	})
 
-	worker := jobs.NewWorker(jobsRepo, pool, cfg.WorkerID, runreportsSvc, runsRepo, resumesRepo, aiClient)
+	registry := jobs.NewRegistry().
+		Register(jobs.JobTypeProcessRun, jobs.NewRunProcessor(jobsRepo, pool, runreportsSvc, runsRepo, resumesRepo, generator, scorer, cfg.ReportMode, cfg.ReportSchemaVersion, usageSvc), jobs.HandlerOptions{})
+
+	worker := jobs.NewWorker(jobsRepo, pool, cfg.WorkerID, registry).
+		WithConcurrency(cfg.WorkerConcurrency).
+		WithShutdownTimeout(cfg.WorkerShutdownTimeout).
+		WithLease(cfg.JobLease)
 
 	// Handle graceful shutdown
 	ctx, cancel := context.WithCancel(ctx)
@@ -72,6 +142,14 @@ func main() {
 		cancel()
 	}()
 
+	// Recover jobs abandoned by crashed workers
+	reaper := jobs.NewReaper(jobsRepo, cfg.JobReapInterval)
+	go func() {
+		if err := reaper.Run(ctx); err != nil && err != context.Canceled {
+			slog.Error("reaper error", "error", err)
+		}
+	}()
+
 	// Run worker
 	if err := worker.Run(ctx); err != nil {
 		if err != context.Canceled {
@@ -90,14 +168,19 @@ type runsRepoAdapter struct {
 
 func (a *runsRepoAdapter) GetRunByID(ctx context.Context, runID uuid.UUID) (jobs.RunData, error) {
 	run, err := a.repo.GetRunByID(ctx, runID)
+	if errors.Is(err, runs.ErrRunNotFound) {
+		return jobs.RunData{}, jobs.ErrRunNotFound
+	}
 	if err != nil {
 		return jobs.RunData{}, err
 	}
 	return jobs.RunData{
 		ID:           run.ID,
+		UserID:       run.UserID,
 		ResumeID:     run.ResumeID,
 		JobText:      run.JobText,
 		Status:       string(run.Status),
 		ErrorMessage: run.ErrorMessage,
+		BypassCache:  run.BypassCache,
 	}, nil
 }
@@ -0,0 +1,153 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	anthropicBaseURL   = "https://api.anthropic.com"
+	anthropicVersion   = "2023-06-01"
+	anthropicMaxTokens = 4096
+)
+
+// AnthropicProvider talks to an Anthropic-style messages API.
+type AnthropicProvider struct {
+	httpClient *http.Client
+	apiKey     string
+	baseURL    string
+	model      string
+}
+
+// NewAnthropicProvider creates an AnthropicProvider. An empty baseURL uses
+// the Anthropic API.
+func NewAnthropicProvider(apiKey, baseURL, model string) *AnthropicProvider {
+	if baseURL == "" {
+		baseURL = anthropicBaseURL
+	}
+	return &AnthropicProvider{
+		httpClient: &http.Client{Timeout: 2 * time.Minute},
+		apiKey:     apiKey,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		model:      model,
+	}
+}
+
+func (p *AnthropicProvider) Name() string  { return ProviderAnthropic }
+func (p *AnthropicProvider) Model() string { return p.model }
+
+type anthropicMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type anthropicRequest struct {
+	Model       string             `json:"model"`
+	MaxTokens   int                `json:"max_tokens"`
+	Temperature *float64           `json:"temperature,omitempty"`
+	System      string             `json:"system,omitempty"`
+	Messages    []anthropicMessage `json:"messages"`
+}
+
+type anthropicResponse struct {
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+	Usage struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
+}
+
+type anthropicError struct {
+	Error struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// Complete sends one user message. The messages API has no JSON mode, so
+// for JSON requests the assistant turn is prefilled with "{" to keep the
+// reply a bare object, and a schema is described in the system prompt.
+func (p *AnthropicProvider) Complete(ctx context.Context, req CompletionRequest) (Completion, error) {
+	system := req.System
+	if req.Schema != nil {
+		schema, err := json.Marshal(req.Schema)
+		if err != nil {
+			return Completion{}, fmt.Errorf("failed to marshal response schema: %w", err)
+		}
+		system += "\n\nThe response must be a JSON object matching this JSON Schema:\n" + string(schema)
+	}
+
+	body := anthropicRequest{
+		Model:       p.model,
+		MaxTokens:   anthropicMaxTokens,
+		Temperature: req.Temperature,
+		System:      system,
+		Messages:    []anthropicMessage{{Role: "user", Content: req.Prompt}},
+	}
+	if req.JSON {
+		body.Messages = append(body.Messages, anthropicMessage{Role: "assistant", Content: "{"})
+	}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return Completion{}, fmt.Errorf("failed to marshal anthropic request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/v1/messages", bytes.NewReader(payload))
+	if err != nil {
+		return Completion{}, fmt.Errorf("failed to build anthropic request: %w", err)
+	}
+	httpReq.Header.Set("content-type", "application/json")
+	httpReq.Header.Set("x-api-key", p.apiKey)
+	httpReq.Header.Set("anthropic-version", anthropicVersion)
+
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return Completion{}, fmt.Errorf("anthropic API error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Completion{}, fmt.Errorf("failed to read anthropic response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		apiErr := &APIError{Provider: ProviderAnthropic, StatusCode: resp.StatusCode}
+		var body anthropicError
+		if json.Unmarshal(raw, &body) == nil && body.Error.Message != "" {
+			apiErr.Message = body.Error.Type + ": " + body.Error.Message
+		}
+		return Completion{}, apiErr
+	}
+
+	var out anthropicResponse
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return Completion{}, fmt.Errorf("failed to parse anthropic response: %w", err)
+	}
+
+	var text strings.Builder
+	if req.JSON {
+		text.WriteString("{")
+	}
+	for _, block := range out.Content {
+		if block.Type == "text" {
+			text.WriteString(block.Text)
+		}
+	}
+
+	return Completion{
+		Content:      text.String(),
+		InputTokens:  out.Usage.InputTokens,
+		OutputTokens: out.Usage.OutputTokens,
+	}, nil
+}
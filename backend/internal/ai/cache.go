@@ -0,0 +1,92 @@
+package ai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+// ReportCache stores validated LLM replies under a content-addressed key so
+// identical inputs are answered without calling the provider again.
+// Expiry is up to the implementation.
+type ReportCache interface {
+	// Get returns the cached reply for key, or false on a miss.
+	Get(ctx context.Context, key string) (CachedReply, bool, error)
+	Put(ctx context.Context, key string, reply CachedReply) error
+}
+
+// CachedReply is a reply that passed schema validation, with what produced
+// it. Content is decoded again on every hit so evidence offsets match the
+// resume text of the current run.
+type CachedReply struct {
+	Provider      string
+	Model         string
+	PromptVersion string
+	SchemaVersion int
+	Temperature   *float64
+	Content       string
+	InputTokens   int
+	OutputTokens  int
+}
+
+type cacheBypassKey struct{}
+
+// WithCacheBypass marks ctx so GenerateRunReport skips cache reads. The
+// fresh reply still replaces the cached one.
+func WithCacheBypass(ctx context.Context) context.Context {
+	return context.WithValue(ctx, cacheBypassKey{}, true)
+}
+
+func cacheBypassed(ctx context.Context) bool {
+	bypass, _ := ctx.Value(cacheBypassKey{}).(bool)
+	return bypass
+}
+
+// cacheKey hashes everything that determines the reply. Scoring signals
+// are derived from the two texts, so they are not part of the key.
+func (c *Client) cacheKey(resumeText, jobText string) string {
+	temperature := "default"
+	if c.temperature != nil {
+		temperature = strconv.FormatFloat(*c.temperature, 'f', -1, 64)
+	}
+
+	h := sha256.New()
+	for _, part := range []string{
+		c.provider.Name(),
+		c.provider.Model(),
+		c.prompt.ID(),
+		temperature,
+		normalizeText(resumeText),
+		normalizeText(jobText),
+	} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// normalizeText collapses whitespace within lines and runs of blank lines,
+// so re-pasting the same text with different spacing or line endings hits
+// the same cache entry.
+func normalizeText(s string) string {
+	var b strings.Builder
+	blank := false
+	for _, line := range strings.Split(strings.TrimSpace(s), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			blank = true
+			continue
+		}
+		if b.Len() > 0 {
+			if blank {
+				b.WriteString("\n")
+			}
+			b.WriteString("\n")
+		}
+		blank = false
+		b.WriteString(strings.Join(fields, " "))
+	}
+	return b.String()
+}
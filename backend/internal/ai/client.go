@@ -4,11 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"strings"
+	"time"
 
-	"github.com/openai/openai-go"
-	"github.com/openai/openai-go/option"
-	"github.com/openai/openai-go/shared"
+	"resume-tailor/internal/scoring"
+	"resume-tailor/internal/scoring/bm25"
+	"resume-tailor/internal/scoring/experience"
 )
 
 // ATSReport represents the ATS scoring report
@@ -22,112 +24,380 @@ type ChangePlan struct {
 	Changes []string `json:"changes"`
 }
 
-// ReportResponse is the expected JSON structure from OpenAI
+// ReportResponse is the JSON structure expected from the LLM for v1
 type ReportResponse struct {
 	ATSReport  ATSReport  `json:"ats_report"`
 	ChangePlan ChangePlan `json:"change_plan"`
 }
 
-// Client wraps the OpenAI client
+// Client generates run reports through an LLM Provider using a fixed
+// prompt version.
 type Client struct {
-	client openai.Client
-	model  string
+	provider    Provider
+	prompt      *Prompt
+	cache       ReportCache
+	temperature *float64
+
+	// contextTokens overrides the model's context window when set;
+	// condense shortens resumes far over budget (map step), else they are
+	// only truncated.
+	contextTokens int
+	condense      *Prompt
 }
 
-// NewClientFromEnv creates a new OpenAI client from environment variables
-func NewClientFromEnv(apiKey, model string) (*Client, error) {
-	if apiKey == "" {
-		return nil, fmt.Errorf("OPENAI_API_KEY is required")
-	}
+// NewClient creates a Client backed by the given provider and prompt.
+func NewClient(provider Provider, prompt *Prompt) *Client {
+	return &Client{provider: provider, prompt: prompt}
+}
+
+// WithCache makes the client answer repeated inputs from cache.
+func (c *Client) WithCache(cache ReportCache) *Client {
+	c.cache = cache
+	return c
+}
+
+// WithContextWindow overrides the context size assumed for the model,
+// for local models missing from the built-in table.
+func (c *Client) WithContextWindow(tokens int) *Client {
+	c.contextTokens = tokens
+	return c
+}
 
-	oc := openai.NewClient(option.WithAPIKey(apiKey))
+// WithCondensePrompt enables condensing very long resumes with the LLM
+// before the report prompt.
+func (c *Client) WithCondensePrompt(prompt *Prompt) *Client {
+	c.condense = prompt
+	return c
+}
 
-	return &Client{
-		client: oc,
-		model:  model,
-	}, nil
+// WithTemperature sets the sampling temperature; unset uses the provider
+// default.
+func (c *Client) WithTemperature(t float64) *Client {
+	c.temperature = &t
+	return c
 }
 
-// GenerateRunReport generates an ATS report and change plan using OpenAI
-func (c *Client) GenerateRunReport(ctx context.Context, resumeText, jobText string, bm25Signals any) (ATSReport, ChangePlan, error) {
-	// Build the prompt
-	prompt := c.buildPrompt(resumeText, jobText, bm25Signals)
+// GeneratorInfo identifies what produced a report.
+type GeneratorInfo struct {
+	Provider      string
+	Model         string
+	PromptVersion string
+	SchemaVersion int
+}
 
-	// Call OpenAI
-	req := openai.ChatCompletionNewParams{
-		Model: c.model,
-		Messages: []openai.ChatCompletionMessageParamUnion{
-			openai.SystemMessage("You are an expert ATS (Applicant Tracking System) analyzer. You analyze resumes against job descriptions and provide structured JSON responses."),
-			openai.UserMessage(prompt),
-		},
-		ResponseFormat: openai.ChatCompletionNewParamsResponseFormatUnion{
-			OfJSONObject: func() *shared.ResponseFormatJSONObjectParam {
-				p := shared.NewResponseFormatJSONObjectParam()
-				return &p
-			}(),
-		},
+// Info returns the provider, model, prompt and schema version used by the
+// client.
+func (c *Client) Info() GeneratorInfo {
+	return GeneratorInfo{
+		Provider:      c.provider.Name(),
+		Model:         c.provider.Model(),
+		PromptVersion: c.prompt.ID(),
+		SchemaVersion: c.prompt.SchemaVersion,
 	}
+}
 
-	resp, err := c.client.Chat.Completions.New(ctx, req)
+// maxRepairAttempts bounds the follow-up requests sent when the reply fails
+// schema validation.
+const maxRepairAttempts = 2
+
+// GenerateRunReport generates an ATS report and change plan using the LLM,
+// in the report schema version of the client's prompt. The reply is
+// validated against that schema; invalid replies are sent back with the
+// violations for repair. Failures are returned as *ReportError.
+//
+// With a cache configured, a valid reply for the same normalized inputs,
+// provider, model, prompt and temperature is reused unless ctx carries
+// WithCacheBypass. Inputs too long for the model's context window are
+// shortened (see fitPrompt) and the report notes what was left out. A
+// check attached with WithCallCheck runs once, after a cache miss and
+// before the first provider call.
+func (c *Client) GenerateRunReport(ctx context.Context, resumeText, jobText string, signals *scoring.Signals) (Report, error) {
+	schema, err := reportSchema(c.prompt.SchemaVersion)
 	if err != nil {
-		return ATSReport{}, ChangePlan{}, fmt.Errorf("OpenAI API error: %w", err)
+		return Report{}, err
 	}
 
-	if len(resp.Choices) == 0 {
-		return ATSReport{}, ChangePlan{}, fmt.Errorf("no choices in OpenAI response")
+	var key string
+	if c.cache != nil {
+		key = c.cacheKey(resumeText, jobText)
+		if !cacheBypassed(ctx) {
+			if report, ok := c.fromCache(ctx, key, resumeText); ok {
+				return report, nil
+			}
+		}
 	}
 
-	content := resp.Choices[0].Message.Content
-	if content == "" {
-		return ATSReport{}, ChangePlan{}, fmt.Errorf("empty content in OpenAI response")
+	if err := runCallCheck(ctx); err != nil {
+		return Report{}, err
 	}
 
-	// Parse JSON response
-	var reportResp ReportResponse
-	if err := json.Unmarshal([]byte(content), &reportResp); err != nil {
-		return ATSReport{}, ChangePlan{}, fmt.Errorf("failed to parse OpenAI JSON response: %w", err)
+	// Build the prompt, shortening the inputs to fit the context window
+	fit, err := c.fitPrompt(ctx, resumeText, jobText, signals)
+	if err != nil {
+		return Report{}, err
+	}
+	if len(fit.notes) > 0 {
+		slog.Info("LLM input shortened to fit the context window", "notes", fit.notes, "model", c.provider.Model())
+	}
+	system, prompt, err := c.prompt.Render(fit.data)
+	if err != nil {
+		return Report{}, err
 	}
 
-	// Validate the response
-	if reportResp.ATSReport.Score < 0 || reportResp.ATSReport.Score > 1 {
-		return ATSReport{}, ChangePlan{}, fmt.Errorf("invalid ATS score: must be between 0 and 1")
+	req := CompletionRequest{
+		System:      system,
+		Prompt:      prompt,
+		JSON:        true,
+		Schema:      schema,
+		SchemaName:  fmt.Sprintf("run_report_v%d", c.prompt.SchemaVersion),
+		Temperature: c.temperature,
 	}
 
-	return reportResp.ATSReport, reportResp.ChangePlan, nil
+	var (
+		violations          []ValidationError
+		calls               = fit.calls
+		inTokens, outTokens int
+	)
+	for attempt := 1; attempt <= maxRepairAttempts+1; attempt++ {
+		resp, call, err := c.complete(ctx, len(calls)+1, req)
+		calls = append(calls, call)
+		if err != nil {
+			return Report{}, &ReportError{
+				Kind:     ErrorKindProvider,
+				Provider: c.provider.Name(),
+				Attempts: attempt,
+				Calls:    calls,
+				Err:      err,
+			}
+		}
+
+		inTokens += resp.InputTokens
+		outTokens += resp.OutputTokens
+
+		content := stripCodeFence(resp.Content)
+		violations = schema.ValidateJSON([]byte(content))
+		if len(violations) == 0 && len(fit.notes) > 0 {
+			if content, err = withNotes(content, fit.notes); err != nil {
+				return Report{}, &ReportError{
+					Kind:     ErrorKindInvalidOutput,
+					Provider: c.provider.Name(),
+					Attempts: attempt,
+					Calls:    calls,
+					Err:      fmt.Errorf("failed to add notes to %s response: %w", c.provider.Name(), err),
+				}
+			}
+		}
+		if len(violations) == 0 {
+			report, err := decodeReport(c.prompt.SchemaVersion, []byte(content), resumeText)
+			if err != nil {
+				return Report{}, &ReportError{
+					Kind:     ErrorKindInvalidOutput,
+					Provider: c.provider.Name(),
+					Attempts: attempt,
+					Calls:    calls,
+					Err:      fmt.Errorf("failed to parse %s JSON response: %w", c.provider.Name(), err),
+				}
+			}
+			report.Calls = calls
+			if c.cache != nil {
+				report.CacheKey = key
+				c.toCache(ctx, key, content, inTokens, outTokens)
+			}
+			return report, nil
+		}
+
+		req.Prompt = repairPrompt(prompt, content, violations)
+	}
+
+	return Report{}, &ReportError{
+		Kind:       ErrorKindInvalidOutput,
+		Provider:   c.provider.Name(),
+		Attempts:   maxRepairAttempts + 1,
+		Violations: violations,
+		Calls:      calls,
+		Err:        fmt.Errorf("%s response failed schema validation", c.provider.Name()),
+	}
 }
 
-func (c *Client) buildPrompt(resumeText, jobText string, bm25Signals any) string {
-	var b strings.Builder
+type callCheckKey struct{}
+
+// WithCallCheck attaches check to ctx. GenerateRunReport calls it before
+// spending anything with the provider, so reports answered from cache are
+// not held to it; its error is returned as is and no call is made.
+func WithCallCheck(ctx context.Context, check func(context.Context) error) context.Context {
+	return context.WithValue(ctx, callCheckKey{}, check)
+}
 
-	b.WriteString("Analyze the following resume against the job description and provide:\n")
-	b.WriteString("1. An ATS compatibility score (0.0 to 1.0)\n")
-	b.WriteString("2. Notes explaining the score\n")
-	b.WriteString("3. A change plan with specific recommendations\n\n")
+func runCallCheck(ctx context.Context) error {
+	check, _ := ctx.Value(callCheckKey{}).(func(context.Context) error)
+	if check == nil {
+		return nil
+	}
+	return check(ctx)
+}
+
+// complete sends one request and describes it as a CallUsage numbered
+// attempt.
+func (c *Client) complete(ctx context.Context, attempt int, req CompletionRequest) (Completion, CallUsage, error) {
+	start := time.Now()
+	resp, err := c.provider.Complete(ctx, req)
+	return resp, CallUsage{
+		Attempt:      attempt,
+		Provider:     c.provider.Name(),
+		Model:        c.provider.Model(),
+		InputTokens:  resp.InputTokens,
+		OutputTokens: resp.OutputTokens,
+		Latency:      time.Since(start),
+		Err:          err,
+	}, err
+}
+
+// withNotes appends notes to the ats_report.notes array of a validated
+// reply; every schema version has one.
+func withNotes(content string, notes []string) (string, error) {
+	var reply map[string]any
+	if err := json.Unmarshal([]byte(content), &reply); err != nil {
+		return "", err
+	}
+	ats, ok := reply["ats_report"].(map[string]any)
+	if !ok {
+		return "", fmt.Errorf("ats_report is not an object")
+	}
+	existing, _ := ats["notes"].([]any)
+	for _, n := range notes {
+		existing = append(existing, n)
+	}
+	ats["notes"] = existing
 
-	b.WriteString("RESUME:\n")
-	b.WriteString(resumeText)
-	b.WriteString("\n\n")
+	out, err := json.Marshal(reply)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
 
-	b.WriteString("JOB DESCRIPTION:\n")
-	b.WriteString(jobText)
-	b.WriteString("\n\n")
+// fromCache returns the cached report for key. Cache failures are logged
+// and treated as misses so they never fail a run.
+func (c *Client) fromCache(ctx context.Context, key, resumeText string) (Report, bool) {
+	reply, ok, err := c.cache.Get(ctx, key)
+	if err != nil {
+		slog.Warn("LLM cache lookup failed", "error", err)
+		return Report{}, false
+	}
+	if !ok || reply.SchemaVersion != c.prompt.SchemaVersion {
+		return Report{}, false
+	}
 
-	if bm25Signals != nil {
-		b.WriteString("BM25 SIGNALS:\n")
-		// If bm25Signals is a struct, we could marshal it, but for now just note it
-		b.WriteString("(BM25 analysis available)\n\n")
+	report, err := decodeReport(reply.SchemaVersion, []byte(reply.Content), resumeText)
+	if err != nil {
+		slog.Warn("cached LLM reply could not be decoded", "error", err, "cache_key", key)
+		return Report{}, false
 	}
+	report.CacheKey = key
+	report.CacheHit = true
+	return report, true
+}
 
-	b.WriteString("Respond with a JSON object in this exact format:\n")
-	b.WriteString(`{
-  "ats_report": {
-    "score": <number between 0.0 and 1.0>,
-    "notes": ["<string>", ...]
-  },
-  "change_plan": {
-    "changes": ["<string>", ...]
-  }
-}`)
+func (c *Client) toCache(ctx context.Context, key, content string, inTokens, outTokens int) {
+	err := c.cache.Put(ctx, key, CachedReply{
+		Provider:      c.provider.Name(),
+		Model:         c.provider.Model(),
+		PromptVersion: c.prompt.ID(),
+		SchemaVersion: c.prompt.SchemaVersion,
+		Temperature:   c.temperature,
+		Content:       content,
+		InputTokens:   inTokens,
+		OutputTokens:  outTokens,
+	})
+	if err != nil {
+		slog.Warn("failed to store LLM reply in cache", "error", err, "cache_key", key)
+	}
+}
 
+// repairPrompt repeats the original task with the rejected reply and the
+// reasons it was rejected.
+func repairPrompt(prompt, previous string, violations []ValidationError) string {
+	var b strings.Builder
+	b.WriteString(prompt)
+	b.WriteString("\n\nYOUR PREVIOUS RESPONSE:\n")
+	b.WriteString(previous)
+	b.WriteString("\n\nIt was rejected because it does not match the required format:\n")
+	for _, v := range violations {
+		b.WriteString("- ")
+		b.WriteString(v.Error())
+		b.WriteString("\n")
+	}
+	b.WriteString("\nReturn only the corrected JSON object.")
 	return b.String()
 }
+
+func promptData(resumeText, jobText string, signals *scoring.Signals) PromptData {
+	data := PromptData{ResumeText: resumeText, JobText: jobText}
+	if signals != nil {
+		data.BM25Signals = formatSignals(&signals.BM25)
+		data.ExperienceSignals = formatExperience(&signals.Experience)
+	}
+	return data
+}
+
+// formatSignals renders the parts of the BM25 output the model needs to
+// ground its recommendations. The full per-term table is omitted to keep the
+// prompt short.
+func formatSignals(s *bm25.Signals) string {
+	summary := struct {
+		Coverage         float64                `json:"coverage"`
+		WeightedCoverage float64                `json:"weighted_coverage"`
+		MatchedTerms     []string               `json:"matched_terms"`
+		MissingTerms     []string               `json:"missing_terms"`
+		MissingRequired  []string               `json:"missing_must_have_terms"`
+		MatchedPhrases   []string               `json:"matched_phrases"`
+		Skills           []bm25.SkillMatch      `json:"skills,omitempty"`
+		Sections         []bm25.SectionCoverage `json:"section_coverage"`
+		TopDocuments     []bm25.DocumentScore   `json:"top_resume_chunks"`
+	}{
+		Coverage:         s.Coverage,
+		WeightedCoverage: s.WeightedCoverage,
+		MatchedTerms:     s.MatchedTerms,
+		MissingTerms:     s.MissingTerms,
+		MissingRequired:  s.MissingRequired,
+		MatchedPhrases:   s.MatchedPhrases,
+		Skills:           s.Skills,
+		Sections:         s.Sections,
+		TopDocuments:     s.TopDocuments,
+	}
+
+	out, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return "(BM25 analysis unavailable)"
+	}
+	return string(out)
+}
+
+// formatExperience renders requirement gaps and seniority. Individual
+// positions are left out since the model already sees the resume.
+func formatExperience(s *experience.Signals) string {
+	gaps := make([]string, 0, len(s.Gaps))
+	for _, g := range s.Gaps {
+		gaps = append(gaps, g.Message)
+	}
+
+	summary := struct {
+		TotalYears   float64                      `json:"total_years"`
+		Skills       []experience.SkillExperience `json:"skill_years"`
+		Requirements []experience.Requirement     `json:"requirements"`
+		Gaps         []string                     `json:"gaps"`
+		Seniority    experience.Seniority         `json:"seniority"`
+	}{
+		TotalYears:   s.TotalYears,
+		Skills:       s.Skills,
+		Requirements: s.Requirements,
+		Gaps:         gaps,
+		Seniority:    s.Seniority,
+	}
+
+	out, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return "(experience analysis unavailable)"
+	}
+	return string(out)
+}
@@ -0,0 +1,120 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// invalidReplyV2 passes JSON parsing but fails ReportSchemaV2.
+func invalidReplyV2() FakeResponse {
+	return FakeResponse{Content: `{"ats_report": {"score": 2}, "change_plan": {"items": []}}`, InputTokens: 10, OutputTokens: 5}
+}
+
+func TestGenerateRunReportRepairs(t *testing.T) {
+	fake := NewFakeProvider("").Enqueue(invalidReplyV2(), validReplyV2())
+	client := newTestClient(t, fake, SchemaV2)
+
+	report, err := client.GenerateRunReport(context.Background(), testResume, testJob, nil)
+	if err != nil {
+		t.Fatalf("GenerateRunReport: %v", err)
+	}
+	if len(report.Calls) != 2 {
+		t.Errorf("%d calls recorded, want 2", len(report.Calls))
+	}
+
+	reqs := fake.Requests()
+	if len(reqs) != 2 {
+		t.Fatalf("%d requests, want 2", len(reqs))
+	}
+	repair := reqs[1].Prompt
+	if !strings.HasPrefix(repair, reqs[0].Prompt) || !strings.Contains(repair, invalidReplyV2().Content) {
+		t.Errorf("repair prompt does not repeat the prompt and the rejected reply")
+	}
+	if !strings.Contains(repair, "$.ats_report.score: must be <= 1") {
+		t.Errorf("repair prompt does not list the violations:\n%s", repair)
+	}
+}
+
+func TestGenerateRunReportRepairExhausted(t *testing.T) {
+	fake := NewFakeProvider("")
+	for range maxRepairAttempts + 1 {
+		fake.Enqueue(invalidReplyV2())
+	}
+	// Never reached: the client gives up after maxRepairAttempts repairs
+	fake.Enqueue(validReplyV2())
+	client := newTestClient(t, fake, SchemaV2)
+
+	_, err := client.GenerateRunReport(context.Background(), testResume, testJob, nil)
+	var reportErr *ReportError
+	if !errors.As(err, &reportErr) {
+		t.Fatalf("got %v, want *ReportError", err)
+	}
+	if reportErr.Kind != ErrorKindInvalidOutput {
+		t.Errorf("kind %q, want %q", reportErr.Kind, ErrorKindInvalidOutput)
+	}
+	if reportErr.Retryable() {
+		t.Error("invalid output is retryable, want not")
+	}
+	if reportErr.Attempts != maxRepairAttempts+1 || len(reportErr.Calls) != maxRepairAttempts+1 {
+		t.Errorf("%d attempts and %d calls, want %d", reportErr.Attempts, len(reportErr.Calls), maxRepairAttempts+1)
+	}
+	for i, call := range reportErr.Calls {
+		if call.Attempt != i+1 || call.InputTokens != 10 || call.OutputTokens != 5 {
+			t.Errorf("call %d: %+v, want attempt %d with 10/5 tokens", i, call, i+1)
+		}
+	}
+	if len(reportErr.Violations) == 0 {
+		t.Error("no violations reported")
+	}
+	if n := len(fake.Requests()); n != maxRepairAttempts+1 {
+		t.Errorf("%d requests sent, want %d", n, maxRepairAttempts+1)
+	}
+}
+
+// mapCache is an in-memory ReportCache.
+type mapCache map[string]CachedReply
+
+func (m mapCache) Get(ctx context.Context, key string) (CachedReply, bool, error) {
+	reply, ok := m[key]
+	return reply, ok, nil
+}
+
+func (m mapCache) Put(ctx context.Context, key string, reply CachedReply) error {
+	m[key] = reply
+	return nil
+}
+
+func TestGenerateRunReportCallCheck(t *testing.T) {
+	errDenied := errors.New("denied")
+	fake := NewFakeProvider("").Enqueue(validReplyV2())
+	client := newTestClient(t, fake, SchemaV2).WithCache(mapCache{})
+
+	// A failing check stops a cache miss before any provider call
+	denied := WithCallCheck(context.Background(), func(context.Context) error { return errDenied })
+	if _, err := client.GenerateRunReport(denied, testResume, testJob, nil); !errors.Is(err, errDenied) {
+		t.Fatalf("cache miss: got %v, want the check's error", err)
+	}
+	if n := len(fake.Requests()); n != 0 {
+		t.Fatalf("cache miss: %d requests sent, want 0", n)
+	}
+
+	checks := 0
+	allowed := WithCallCheck(context.Background(), func(context.Context) error { checks++; return nil })
+	if _, err := client.GenerateRunReport(allowed, testResume, testJob, nil); err != nil {
+		t.Fatalf("allowed: %v", err)
+	}
+	if checks != 1 {
+		t.Errorf("check ran %d times, want 1", checks)
+	}
+
+	// A cache hit needs no provider call, so the check is not consulted
+	report, err := client.GenerateRunReport(denied, testResume, testJob, nil)
+	if err != nil {
+		t.Fatalf("cache hit: %v", err)
+	}
+	if !report.CacheHit || len(fake.Requests()) != 1 {
+		t.Errorf("cache hit %v with %d requests, want a hit after 1 request", report.CacheHit, len(fake.Requests()))
+	}
+}
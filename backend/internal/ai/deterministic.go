@@ -0,0 +1,171 @@
+package ai
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"resume-tailor/internal/scoring"
+	"resume-tailor/internal/scoring/experience"
+	"resume-tailor/internal/scoring/jobpost"
+	"resume-tailor/internal/scoring/sections"
+)
+
+// Weights of the deterministic score components; they sum to 1.
+const (
+	keywordWeight    = 0.7
+	experienceWeight = 0.2
+	seniorityWeight  = 0.1
+)
+
+const maxListedTerms = 8
+
+// GenerateDeterministicReport builds an ATS report and change plan from the
+// scoring signals alone, in the given schema version. It needs no model or
+// network access and always gives the same output for the same input.
+func GenerateDeterministicReport(resumeText string, signals *scoring.Signals, schemaVersion int) (Report, error) {
+	if signals == nil {
+		return Report{}, fmt.Errorf("scoring signals are required for a deterministic report")
+	}
+
+	switch schemaVersion {
+	case SchemaV1:
+		report, plan := deterministicV1(signals)
+		return newReport(SchemaV1, report, plan)
+	case SchemaV2:
+		report, plan := deterministicV2(resumeText, signals)
+		return newReport(SchemaV2, report, plan)
+	default:
+		return Report{}, fmt.Errorf("unsupported report schema version %d", schemaVersion)
+	}
+}
+
+func deterministicV1(signals *scoring.Signals) (ATSReport, ChangePlan) {
+	kw, exp := &signals.BM25, &signals.Experience
+
+	report := ATSReport{
+		Score: deterministicScore(signals),
+		Notes: []string{},
+	}
+	plan := ChangePlan{Changes: []string{}}
+
+	report.Notes = append(report.Notes, fmt.Sprintf(
+		"Keyword coverage: %s of posting terms found in the resume (%s weighted by requirement group).",
+		percent(kw.Coverage), percent(kw.WeightedCoverage)))
+
+	var missingRequired, missingPreferred []string
+	for _, s := range kw.Skills {
+		switch {
+		case !s.Matched && s.Group == jobpost.GroupRequired:
+			missingRequired = append(missingRequired, s.Name)
+			plan.Changes = append(plan.Changes, fmt.Sprintf(
+				"Add %s if you have used it, ideally in an Experience bullet that shows what you built with it.", s.Name))
+		case !s.Matched && s.Group == jobpost.GroupPreferred:
+			missingPreferred = append(missingPreferred, s.Name)
+		case s.Matched && onlyInSkills(s.ResumeSections):
+			plan.Changes = append(plan.Changes, fmt.Sprintf(
+				"%s is only listed under Skills; show it in an Experience or Projects bullet.", s.Name))
+		}
+	}
+	if len(missingRequired) > 0 {
+		report.Notes = append(report.Notes, "Missing must-have skills: "+strings.Join(missingRequired, ", ")+".")
+	}
+	if len(missingPreferred) > 0 {
+		report.Notes = append(report.Notes, "Missing nice-to-have skills: "+strings.Join(missingPreferred, ", ")+".")
+		plan.Changes = append(plan.Changes, fmt.Sprintf(
+			"If you have experience with %s, mention it; the posting lists it as a plus.", strings.Join(missingPreferred, ", ")))
+	}
+
+	if terms := firstN(kw.MissingRequired, maxListedTerms); len(terms) > 0 {
+		report.Notes = append(report.Notes, "Must-have terms not found: "+strings.Join(terms, ", ")+".")
+		plan.Changes = append(plan.Changes, fmt.Sprintf(
+			"Work these must-have terms from the posting into the resume where accurate: %s.", strings.Join(terms, ", ")))
+	}
+
+	for _, g := range exp.Gaps {
+		report.Notes = append(report.Notes, "Experience gap: "+g.Message+".")
+		if g.Skill == "" {
+			plan.Changes = append(plan.Changes,
+				"Give start and end dates for every relevant role so your total experience is easy to verify.")
+			continue
+		}
+		plan.Changes = append(plan.Changes, fmt.Sprintf(
+			"Name %s in each role where you used it so the full duration is credited (posting asks for %sy).",
+			g.Name, formatYears(g.RequiredYears)))
+	}
+
+	if !exp.Seniority.Met {
+		report.Notes = append(report.Notes, fmt.Sprintf(
+			"Seniority: posting targets a %s role; resume reads as %s.", exp.Seniority.Job, exp.Seniority.Resume))
+		plan.Changes = append(plan.Changes, fmt.Sprintf(
+			"Highlight scope and ownership (team size, systems owned, decisions led) that matches a %s role.", exp.Seniority.Job))
+	}
+
+	if len(plan.Changes) == 0 {
+		plan.Changes = append(plan.Changes, "The resume already covers the posting well; tailor the summary to the role title.")
+	}
+
+	return report, plan
+}
+
+// deterministicScore blends keyword coverage, years-of-experience
+// requirements and seniority into a 0..1 score rounded to two decimals.
+func deterministicScore(signals *scoring.Signals) float64 {
+	keyword := signals.BM25.WeightedCoverage
+
+	experienceScore := requirementsMet(signals.Experience.Requirements)
+
+	seniority := 1.0
+	if !signals.Experience.Seniority.Met {
+		seniority = 0.5
+	}
+
+	score := keywordWeight*keyword + experienceWeight*experienceScore + seniorityWeight*seniority
+	return math.Round(math.Max(0, math.Min(1, score))*100) / 100
+}
+
+// requirementsMet is the share of must-have experience requirements that are
+// met, falling back to all requirements when none are must-haves.
+func requirementsMet(reqs []experience.Requirement) float64 {
+	total, met := 0, 0
+	for _, r := range reqs {
+		if r.Group != jobpost.GroupRequired {
+			continue
+		}
+		total++
+		if r.Met {
+			met++
+		}
+	}
+	if total == 0 {
+		for _, r := range reqs {
+			total++
+			if r.Met {
+				met++
+			}
+		}
+	}
+	if total == 0 {
+		return 1
+	}
+	return float64(met) / float64(total)
+}
+
+func onlyInSkills(kinds []sections.Kind) bool {
+	return len(kinds) == 1 && kinds[0] == sections.KindSkills
+}
+
+func firstN(list []string, n int) []string {
+	if len(list) > n {
+		return list[:n]
+	}
+	return list
+}
+
+func percent(f float64) string {
+	return fmt.Sprintf("%.0f%%", f*100)
+}
+
+func formatYears(y float64) string {
+	return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.1f", y), "0"), ".")
+}
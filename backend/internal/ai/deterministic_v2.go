@@ -0,0 +1,273 @@
+package ai
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+
+	"resume-tailor/internal/scoring"
+	"resume-tailor/internal/scoring/jobpost"
+	"resume-tailor/internal/scoring/sections"
+)
+
+// Weights of the v2 sub-scores in the overall score; they sum to 1.
+const (
+	v2KeywordWeight    = 0.6
+	v2ExperienceWeight = 0.2
+	v2FormattingWeight = 0.1
+	v2ImpactWeight     = 0.1
+)
+
+const maxEvidencePerSkill = 2
+
+// impactPattern matches quantified results: 40%, $2M, 3x, 10k, plain numbers.
+var impactPattern = regexp.MustCompile(`(?i)\d+(?:[.,]\d+)?\s*(?:%|x\b|k\b|m\b|\+)|[$€£]\s*\d|\b\d{2,}\b`)
+
+func deterministicV2(resumeText string, signals *scoring.Signals) (ATSReportV2, ChangePlanV2) {
+	kw, exp := &signals.BM25, &signals.Experience
+	secs := sections.Detect(resumeText)
+	bullets := experienceBullets(secs)
+
+	sub := SubScores{
+		KeywordCoverage: round2(kw.WeightedCoverage),
+		ExperienceFit:   round2(experienceFit(signals)),
+		Formatting:      round2(formattingScore(secs, len(exp.Positions))),
+		Impact:          round2(impactScore(bullets)),
+	}
+	report := ATSReportV2{
+		SchemaVersion: SchemaV2,
+		Score: round2(v2KeywordWeight*sub.KeywordCoverage + v2ExperienceWeight*sub.ExperienceFit +
+			v2FormattingWeight*sub.Formatting + v2ImpactWeight*sub.Impact),
+		SubScores:     sub,
+		MatchedSkills: []SkillEvidence{},
+		MissingSkills: []MissingSkill{},
+	}
+	v1, _ := deterministicV1(signals)
+	report.Notes = v1.Notes
+	plan := ChangePlanV2{SchemaVersion: SchemaV2, Items: []ChangeItem{}}
+	add := func(item ChangeItem) {
+		item.ID = fmt.Sprintf("c%d", len(plan.Items)+1)
+		if item.Terms == nil {
+			item.Terms = []string{}
+		}
+		plan.Items = append(plan.Items, item)
+	}
+
+	var missingPreferred []string
+	for _, s := range kw.Skills {
+		terms := s.JobAliases
+		if s.Matched {
+			report.MatchedSkills = append(report.MatchedSkills, SkillEvidence{
+				Skill:    s.Name,
+				Evidence: skillEvidence(resumeText, secs, s.ResumeAliases),
+			})
+			if onlyInSkills(s.ResumeSections) {
+				add(ChangeItem{
+					Priority:      "medium",
+					Category:      "experience",
+					TargetSection: string(sections.KindExperience),
+					SuggestedText: fmt.Sprintf("Name %s in the Experience bullet that describes where you used it.", s.Name),
+					Rationale:     fmt.Sprintf("%s is only listed under Skills; evidence in Experience weighs more with ATS and recruiters.", s.Name),
+					Terms:         terms,
+				})
+			}
+			continue
+		}
+
+		importance := "required"
+		if s.Group == jobpost.GroupPreferred {
+			importance = "preferred"
+			missingPreferred = append(missingPreferred, s.Name)
+		}
+		report.MissingSkills = append(report.MissingSkills, MissingSkill{Skill: s.Name, Importance: importance})
+
+		if s.Group == jobpost.GroupRequired {
+			add(ChangeItem{
+				Priority:      "high",
+				Category:      "keywords",
+				TargetSection: string(sections.KindExperience),
+				SuggestedText: fmt.Sprintf("Add a bullet showing what you built with %s, if you have used it.", s.Name),
+				Rationale:     fmt.Sprintf("The posting lists %s as a must-have and the resume does not mention it.", s.Name),
+				Terms:         terms,
+			})
+		}
+	}
+	if len(missingPreferred) > 0 {
+		add(ChangeItem{
+			Priority:      "low",
+			Category:      "skills",
+			TargetSection: string(sections.KindSkills),
+			SuggestedText: fmt.Sprintf("List %s under Skills if you have hands-on experience.", strings.Join(missingPreferred, ", ")),
+			Rationale:     "The posting lists these as nice to have.",
+			Terms:         missingPreferred,
+		})
+	}
+
+	if terms := firstN(kw.MissingRequired, maxListedTerms); len(terms) > 0 {
+		add(ChangeItem{
+			Priority:      "medium",
+			Category:      "keywords",
+			TargetSection: string(sections.KindSummary),
+			SuggestedText: fmt.Sprintf("Work these terms into the summary or relevant bullets where accurate: %s.", strings.Join(terms, ", ")),
+			Rationale:     "These must-have terms from the posting do not appear in the resume.",
+			Terms:         terms,
+		})
+	}
+
+	for _, g := range exp.Gaps {
+		priority := "high"
+		if g.Group != jobpost.GroupRequired {
+			priority = "low"
+		}
+		item := ChangeItem{
+			Priority:      priority,
+			Category:      "experience",
+			TargetSection: string(sections.KindExperience),
+			SuggestedText: "Give start and end dates for every relevant role so your total experience is easy to verify.",
+			Rationale:     g.Message + ".",
+		}
+		if g.Skill != "" {
+			item.SuggestedText = fmt.Sprintf("Name %s in each role where you used it so the full duration is credited.", g.Name)
+			item.Terms = []string{g.Skill}
+		}
+		add(item)
+	}
+
+	if !exp.Seniority.Met {
+		add(ChangeItem{
+			Priority:      "medium",
+			Category:      "summary",
+			TargetSection: string(sections.KindSummary),
+			SuggestedText: fmt.Sprintf("Open the summary with the scope you have owned (team size, systems, decisions) that matches a %s role.", exp.Seniority.Job),
+			Rationale:     fmt.Sprintf("The posting targets a %s role; the resume reads as %s.", exp.Seniority.Job, exp.Seniority.Resume),
+		})
+	}
+
+	if sub.Impact < 0.5 {
+		item := ChangeItem{
+			Priority:      "medium",
+			Category:      "impact",
+			TargetSection: string(sections.KindExperience),
+			SuggestedText: "Rewrite experience bullets to lead with a measurable result (latency, revenue, users, time saved).",
+			Rationale:     "Few experience bullets quantify their outcome.",
+		}
+		for _, b := range bullets {
+			if !impactPattern.MatchString(b) {
+				item.OriginalText = b
+				break
+			}
+		}
+		add(item)
+	}
+
+	if len(plan.Items) == 0 {
+		add(ChangeItem{
+			Priority:      "low",
+			Category:      "summary",
+			TargetSection: string(sections.KindSummary),
+			SuggestedText: "Tailor the summary to the role title in the posting.",
+			Rationale:     "The resume already covers the posting well.",
+		})
+	}
+
+	return report, plan
+}
+
+// experienceFit combines years-of-experience requirements and seniority.
+func experienceFit(signals *scoring.Signals) float64 {
+	seniority := 1.0
+	if !signals.Experience.Seniority.Met {
+		seniority = 0.5
+	}
+	return 0.8*requirementsMet(signals.Experience.Requirements) + 0.2*seniority
+}
+
+// formattingScore rewards the sections an ATS looks for and dated roles.
+func formattingScore(secs []sections.Section, positions int) float64 {
+	found := make(map[sections.Kind]bool)
+	for _, s := range secs {
+		found[s.Kind] = true
+	}
+
+	score := 0.0
+	for _, kind := range []sections.Kind{sections.KindExperience, sections.KindEducation, sections.KindSkills} {
+		if found[kind] {
+			score += 0.25
+		}
+	}
+	if positions > 0 {
+		score += 0.25
+	}
+	return score
+}
+
+// impactScore is the share of experience bullets that quantify a result.
+func impactScore(bullets []string) float64 {
+	if len(bullets) == 0 {
+		return 0
+	}
+	n := 0
+	for _, b := range bullets {
+		if impactPattern.MatchString(b) {
+			n++
+		}
+	}
+	return float64(n) / float64(len(bullets))
+}
+
+func experienceBullets(secs []sections.Section) []string {
+	var out []string
+	for _, s := range secs {
+		if s.Kind != sections.KindExperience && s.Kind != sections.KindProjects {
+			continue
+		}
+		for _, line := range strings.Split(s.Text, "\n") {
+			line = strings.TrimSpace(line)
+			if isBulletLine(line) {
+				out = append(out, trimBulletMarker(line))
+			}
+		}
+	}
+	return out
+}
+
+func isBulletLine(line string) bool {
+	return strings.IndexAny(line, "-*•·–▪") == 0
+}
+
+func trimBulletMarker(line string) string {
+	return strings.TrimSpace(strings.TrimLeft(line, "-*•·–▪"))
+}
+
+// skillEvidence quotes the first resume lines that mention any alias.
+func skillEvidence(resumeText string, secs []sections.Section, aliases []string) []EvidenceSpan {
+	patterns := make([]*regexp.Regexp, 0, len(aliases))
+	for _, a := range aliases {
+		patterns = append(patterns, regexp.MustCompile(`(?i)(?:^|[^\pL\pN])`+regexp.QuoteMeta(a)+`(?:$|[^\pL\pN+#])`))
+	}
+
+	var spans []EvidenceSpan
+	for _, s := range secs {
+		for _, line := range strings.Split(s.Text, "\n") {
+			line = trimBulletMarker(line)
+			if line == "" {
+				continue
+			}
+			for _, p := range patterns {
+				if p.MatchString(line) {
+					spans = append(spans, EvidenceSpan{Section: string(s.Kind), Quote: line})
+					break
+				}
+			}
+			if len(spans) == maxEvidencePerSkill {
+				return locateEvidence(resumeText, spans)
+			}
+		}
+	}
+	return locateEvidence(resumeText, spans)
+}
+
+func round2(f float64) float64 {
+	return math.Round(math.Max(0, math.Min(1, f))*100) / 100
+}
@@ -0,0 +1,97 @@
+package ai
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// APIError is a non-2xx response from an LLM vendor API.
+type APIError struct {
+	Provider   string
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("%s API error: %d", e.Provider, e.StatusCode)
+	}
+	return fmt.Sprintf("%s API error: %d %s", e.Provider, e.StatusCode, e.Message)
+}
+
+// ErrorKind classifies a report generation failure.
+type ErrorKind string
+
+const (
+	// ErrorKindProvider is a failed call to the provider (transport, HTTP
+	// status, empty reply).
+	ErrorKindProvider ErrorKind = "provider"
+	// ErrorKindInvalidOutput means the reply still failed schema validation
+	// after the repair round-trips.
+	ErrorKindInvalidOutput ErrorKind = "invalid_output"
+	// ErrorKindInputTooLarge means the prompt cannot be fitted into the
+	// model's context window.
+	ErrorKindInputTooLarge ErrorKind = "input_too_large"
+)
+
+// ReportError is returned by GenerateRunReport so callers can decide
+// whether the run is worth retrying. Calls lists the provider calls made
+// before giving up, which are billed all the same.
+type ReportError struct {
+	Kind       ErrorKind
+	Provider   string
+	Attempts   int
+	Violations []ValidationError
+	Calls      []CallUsage
+	Err        error
+}
+
+func (e *ReportError) Error() string {
+	if len(e.Violations) == 0 {
+		return e.Err.Error()
+	}
+	msgs := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		msgs[i] = v.Error()
+	}
+	return fmt.Sprintf("%v after %d attempts: %s", e.Err, e.Attempts, strings.Join(msgs, "; "))
+}
+
+func (e *ReportError) Unwrap() error {
+	return e.Err
+}
+
+// Retryable reports whether the same request may succeed later. Rate
+// limits, server errors and transport failures are retryable; bad requests,
+// auth failures and output that could not be repaired are not.
+func (e *ReportError) Retryable() bool {
+	if e.Kind == ErrorKindInvalidOutput || e.Kind == ErrorKindInputTooLarge {
+		return false
+	}
+
+	status, ok := statusCode(e.Err)
+	if !ok {
+		return true
+	}
+	switch {
+	case status == http.StatusRequestTimeout,
+		status == http.StatusConflict,
+		status == http.StatusTooEarly,
+		status == http.StatusTooManyRequests,
+		status >= 500:
+		return true
+	default:
+		return false
+	}
+}
+
+// statusCode extracts the HTTP status from a provider error, if any.
+func statusCode(err error) (int, bool) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode, true
+	}
+	return 0, false
+}
@@ -0,0 +1,263 @@
+package ai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FakeResponse is one scripted reply. Err and Empty simulate failures; Latency
+// delays the reply (honouring context cancellation).
+type FakeResponse struct {
+	Content      string
+	Err          error
+	Empty        bool
+	Latency      time.Duration
+	InputTokens  int
+	OutputTokens int
+}
+
+// FakeProvider is a scripted Provider for tests and local development.
+// Replies are chosen in order: a response registered for the request's
+// fingerprint, then the next queued response, then the rule.
+type FakeProvider struct {
+	mu        sync.Mutex
+	model     string
+	latency   time.Duration
+	responses map[string]FakeResponse
+	queue     []FakeResponse
+	rule      func(CompletionRequest) FakeResponse
+	requests  []CompletionRequest
+}
+
+// NewFakeProvider creates a FakeProvider that answers every request with
+// RuleResponse until scripted otherwise.
+func NewFakeProvider(model string) *FakeProvider {
+	if model == "" {
+		model = ProviderFake
+	}
+	return &FakeProvider{
+		model:     model,
+		responses: make(map[string]FakeResponse),
+		rule:      RuleResponse,
+	}
+}
+
+func (p *FakeProvider) Name() string  { return ProviderFake }
+func (p *FakeProvider) Model() string { return p.model }
+
+// On registers the reply for requests with the given fingerprint.
+func (p *FakeProvider) On(fingerprint string, resp FakeResponse) *FakeProvider {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.responses[fingerprint] = resp
+	return p
+}
+
+// Enqueue adds replies consumed one per request.
+func (p *FakeProvider) Enqueue(resps ...FakeResponse) *FakeProvider {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.queue = append(p.queue, resps...)
+	return p
+}
+
+// WithRule replaces the fallback used when nothing is scripted.
+func (p *FakeProvider) WithRule(rule func(CompletionRequest) FakeResponse) *FakeProvider {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.rule = rule
+	return p
+}
+
+// WithLatency delays every reply that has no latency of its own.
+func (p *FakeProvider) WithLatency(d time.Duration) *FakeProvider {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.latency = d
+	return p
+}
+
+// Requests returns a copy of every request received so far.
+func (p *FakeProvider) Requests() []CompletionRequest {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	out := make([]CompletionRequest, len(p.requests))
+	copy(out, p.requests)
+	return out
+}
+
+func (p *FakeProvider) Complete(ctx context.Context, req CompletionRequest) (Completion, error) {
+	resp := p.next(req)
+
+	if resp.Latency > 0 {
+		timer := time.NewTimer(resp.Latency)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return Completion{}, ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	if resp.Err != nil {
+		return Completion{}, resp.Err
+	}
+	if resp.Empty {
+		return Completion{}, fmt.Errorf("no choices in %s response", ProviderFake)
+	}
+
+	return Completion{
+		Content:      resp.Content,
+		InputTokens:  resp.InputTokens,
+		OutputTokens: resp.OutputTokens,
+	}, nil
+}
+
+func (p *FakeProvider) next(req CompletionRequest) FakeResponse {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.requests = append(p.requests, req)
+
+	var resp FakeResponse
+	if r, ok := p.responses[Fingerprint(req)]; ok {
+		resp = r
+	} else if len(p.queue) > 0 {
+		resp, p.queue = p.queue[0], p.queue[1:]
+	} else {
+		resp = p.rule(req)
+	}
+
+	if resp.Latency == 0 {
+		resp.Latency = p.latency
+	}
+	return resp
+}
+
+// Fingerprint identifies a request by its system and user prompts.
+func Fingerprint(req CompletionRequest) string {
+	sum := sha256.Sum256([]byte(req.System + "\x00" + req.Prompt))
+	return hex.EncodeToString(sum[:8])
+}
+
+// FakeJSON scripts a reply containing v marshalled as JSON.
+func FakeJSON(v any) FakeResponse {
+	out, err := json.Marshal(v)
+	if err != nil {
+		return FakeResponse{Err: err}
+	}
+	return FakeResponse{Content: string(out)}
+}
+
+// FakeMalformed scripts a truncated JSON reply.
+func FakeMalformed() FakeResponse {
+	return FakeResponse{Content: `{"ats_report": {"score": 0.5, "notes": [`}
+}
+
+// FakeEmpty scripts a reply with no choices.
+func FakeEmpty() FakeResponse {
+	return FakeResponse{Empty: true}
+}
+
+// FakeError scripts an API error with the given HTTP status.
+func FakeError(status int, message string) FakeResponse {
+	return FakeResponse{Err: &APIError{Provider: ProviderFake, StatusCode: status, Message: message}}
+}
+
+var (
+	signalsPattern = regexp.MustCompile(`(?s)BM25 SIGNALS[^\n]*\n(\{\n.*?\n\})\n`)
+	excerptPattern = regexp.MustCompile(`(?s)EXCERPT:\n(.*)\nEND EXCERPT`)
+)
+
+// promptSignals reads back the parts of the BM25 signals (see
+// formatSignals) the fake provider answers from.
+func promptSignals(prompt string) (score float64, missing []string) {
+	score = 0.5
+	m := signalsPattern.FindStringSubmatch(prompt)
+	if m == nil {
+		return score, nil
+	}
+	var signals struct {
+		WeightedCoverage *float64 `json:"weighted_coverage"`
+		MissingRequired  []string `json:"missing_must_have_terms"`
+	}
+	if err := json.Unmarshal([]byte(m[1]), &signals); err != nil {
+		return score, nil
+	}
+	if v := signals.WeightedCoverage; v != nil && *v >= 0 && *v <= 1 {
+		score = *v
+	}
+	return score, signals.MissingRequired
+}
+
+// RuleResponse generates a valid report in the requested schema version:
+// the score is the weighted keyword coverage found in the BM25 signals (0.5
+// without them), and the must-have terms missing from the resume are
+// reported as missing skills with a change adding them. Plain-text requests
+// (resume condensing) get the first half of the excerpt's lines back.
+func RuleResponse(req CompletionRequest) FakeResponse {
+	if !req.JSON {
+		var excerpt string
+		if m := excerptPattern.FindStringSubmatch(req.Prompt); m != nil {
+			lines := strings.Split(m[1], "\n")
+			excerpt = strings.Join(lines[:(len(lines)+1)/2], "\n")
+		}
+		return FakeResponse{
+			Content:      excerpt,
+			InputTokens:  len(req.System+req.Prompt) / 4,
+			OutputTokens: len(excerpt) / 4,
+		}
+	}
+
+	score, missing := promptSignals(req.Prompt)
+	note := "Generated by the fake LLM provider for prompt " + Fingerprint(req) + "."
+	change := "No changes suggested by the fake LLM provider."
+	if len(missing) > 0 {
+		change = fmt.Sprintf("Add %s to the resume where accurate.", strings.Join(missing, ", "))
+	}
+
+	var resp FakeResponse
+	if req.Schema == ReportSchemaV2 {
+		item := ChangeItem{
+			ID:            "c1",
+			Priority:      "low",
+			Category:      "summary",
+			TargetSection: "summary",
+			SuggestedText: change,
+			Rationale:     note,
+			Terms:         []string{},
+		}
+		missingSkills := []MissingSkill{}
+		if len(missing) > 0 {
+			item.Priority, item.Category, item.Terms = "high", "keywords", missing
+			for _, term := range missing {
+				missingSkills = append(missingSkills, MissingSkill{Skill: term, Importance: "required"})
+			}
+		}
+		resp = FakeJSON(ReportResponseV2{
+			ATSReport: ATSReportV2{
+				Score:         score,
+				SubScores:     SubScores{KeywordCoverage: score, ExperienceFit: score, Formatting: score, Impact: score},
+				MatchedSkills: []SkillEvidence{},
+				MissingSkills: missingSkills,
+				Notes:         []string{note},
+			},
+			ChangePlan: ChangePlanV2{Items: []ChangeItem{item}},
+		})
+	} else {
+		resp = FakeJSON(ReportResponse{
+			ATSReport:  ATSReport{Score: score, Notes: []string{note}},
+			ChangePlan: ChangePlan{Changes: []string{change}},
+		})
+	}
+	resp.InputTokens = len(req.System+req.Prompt) / 4
+	resp.OutputTokens = len(resp.Content) / 4
+	return resp
+}
@@ -0,0 +1,122 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const (
+	testResume = `Jane Doe
+Summary
+Backend engineer with 6 years of experience building Go services.
+Experience
+Senior Engineer, Acme (2019 - present)
+- Built Go microservices on PostgreSQL, cutting p99 latency by 40%.
+Skills
+Go, PostgreSQL, Docker`
+
+	testJob = `Senior Backend Engineer
+Requirements
+- 5+ years of experience with Go and PostgreSQL
+- Experience with Kubernetes
+Nice to have
+- Terraform`
+)
+
+// validReplyV2 is a minimal reply that passes ReportSchemaV2.
+func validReplyV2() FakeResponse {
+	resp := FakeJSON(ReportResponseV2{
+		ATSReport: ATSReportV2{
+			Score:         0.7,
+			SubScores:     SubScores{KeywordCoverage: 0.6, ExperienceFit: 0.8, Formatting: 0.7, Impact: 0.6},
+			MatchedSkills: []SkillEvidence{{Skill: "Go", Evidence: []EvidenceSpan{{Section: "skills", Quote: "Go, PostgreSQL"}}}},
+			MissingSkills: []MissingSkill{{Skill: "Kubernetes", Importance: "required"}},
+			Notes:         []string{"Strong Go background."},
+		},
+		ChangePlan: ChangePlanV2{
+			Items: []ChangeItem{{
+				ID:            "c1",
+				Priority:      "high",
+				Category:      "keywords",
+				TargetSection: "skills",
+				SuggestedText: "Add Kubernetes if you have used it.",
+				Rationale:     "The posting requires Kubernetes.",
+				Terms:         []string{"kubernetes"},
+			}},
+		},
+	})
+	resp.InputTokens, resp.OutputTokens = 100, 50
+	return resp
+}
+
+func newTestClient(t *testing.T, provider Provider, schemaVersion int) *Client {
+	t.Helper()
+	prompts, err := LoadPrompts()
+	if err != nil {
+		t.Fatalf("LoadPrompts: %v", err)
+	}
+	prompt, err := prompts.Latest(PromptRunReport, schemaVersion)
+	if err != nil {
+		t.Fatalf("Latest: %v", err)
+	}
+	return NewClient(provider, prompt)
+}
+
+// TestGenerateRunReportFakeProvider scripts a malformed reply, an empty
+// reply, a rate limit and a valid reply, and checks how each run of the
+// report pipeline ends.
+func TestGenerateRunReportFakeProvider(t *testing.T) {
+	ctx := context.Background()
+	fake := NewFakeProvider("")
+	fake.Enqueue(FakeMalformed(), FakeEmpty(), FakeError(http.StatusTooManyRequests, "rate limited"), validReplyV2())
+	client := newTestClient(t, fake, SchemaV2)
+
+	// The malformed reply is sent back for repair; the repair gets an empty
+	// reply, which fails the attempt with a retryable provider error
+	_, err := client.GenerateRunReport(ctx, testResume, testJob, nil)
+	var reportErr *ReportError
+	if !errors.As(err, &reportErr) {
+		t.Fatalf("first run: got %v, want *ReportError", err)
+	}
+	if reportErr.Kind != ErrorKindProvider || !reportErr.Retryable() {
+		t.Errorf("first run: kind %q retryable %v, want retryable provider error", reportErr.Kind, reportErr.Retryable())
+	}
+	if len(reportErr.Calls) != 2 {
+		t.Errorf("first run: %d calls recorded, want 2", len(reportErr.Calls))
+	}
+	reqs := fake.Requests()
+	if len(reqs) != 2 || !strings.Contains(reqs[1].Prompt, "YOUR PREVIOUS RESPONSE:") || !strings.Contains(reqs[1].Prompt, "invalid JSON") {
+		t.Errorf("first run: second request is not a repair prompt")
+	}
+
+	// The rate limit fails the next run; it is worth retrying
+	_, err = client.GenerateRunReport(ctx, testResume, testJob, nil)
+	if !errors.As(err, &reportErr) {
+		t.Fatalf("second run: got %v, want *ReportError", err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("second run: got %v, want a 429 APIError", err)
+	}
+	if !reportErr.Retryable() || len(reportErr.Calls) != 1 {
+		t.Errorf("second run: retryable %v with %d calls, want retryable with 1 call", reportErr.Retryable(), len(reportErr.Calls))
+	}
+
+	// The valid reply produces the report
+	report, err := client.GenerateRunReport(ctx, testResume, testJob, nil)
+	if err != nil {
+		t.Fatalf("third run: %v", err)
+	}
+	if report.SchemaVersion != SchemaV2 {
+		t.Errorf("third run: schema version %d, want %d", report.SchemaVersion, SchemaV2)
+	}
+	if len(report.Calls) != 1 || report.Calls[0].InputTokens != 100 || report.Calls[0].OutputTokens != 50 {
+		t.Errorf("third run: calls %+v, want one call with 100/50 tokens", report.Calls)
+	}
+	if !strings.Contains(string(report.ATSReport), `"Kubernetes"`) {
+		t.Errorf("third run: ATS report %s does not carry the reply", report.ATSReport)
+	}
+}
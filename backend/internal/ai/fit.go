@@ -0,0 +1,482 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"resume-tailor/internal/scoring"
+	"resume-tailor/internal/scoring/bm25"
+	"resume-tailor/internal/scoring/jobpost"
+	"resume-tailor/internal/scoring/sections"
+)
+
+const (
+	// outputReserveTokens is left free for the reply.
+	outputReserveTokens = 4096
+	// fitMargin absorbs the error of EstimateTokens.
+	fitMargin = 0.9
+	// minTextTokens is the least room worth sending resume and posting in.
+	minTextTokens = 512
+	// jobShare is the part of the text budget the posting may take when
+	// both texts are too long.
+	jobShare = 0.3
+	// condenseRatio: a resume more than this many times over its budget is
+	// condensed by the LLM before truncation.
+	condenseRatio = 2.0
+)
+
+// PromptCondenseResume is the prompt used to shorten resume excerpts.
+const PromptCondenseResume = "condense_resume"
+
+// CondenseData is the input to the condense_resume prompt.
+type CondenseData struct {
+	Excerpt     string
+	TargetWords int
+	Terms       string
+}
+
+// fitted is the prompt data after fitting it into the model's context
+// window, with notes for the report describing what was left out.
+type fitted struct {
+	data  PromptData
+	notes []string
+	calls []CallUsage
+}
+
+// contextWindow returns the configured context size, or the model's.
+func (c *Client) contextWindow() int {
+	if c.contextTokens > 0 {
+		return c.contextTokens
+	}
+	return ContextWindow(c.provider.Model())
+}
+
+func (c *Client) promptTokens(data PromptData) (int, error) {
+	system, user, err := c.prompt.Render(data)
+	if err != nil {
+		return 0, err
+	}
+	model := c.provider.Model()
+	return EstimateTokens(model, system) + EstimateTokens(model, user), nil
+}
+
+// fitPrompt shrinks the prompt inputs until the rendered prompt leaves
+// room for the reply. In order: BM25 signals are abbreviated when they
+// crowd out the texts, the posting keeps its requirement lines first, a
+// resume far over budget is condensed chunk by chunk by the LLM (map) and
+// reassembled for the report prompt (reduce), and finally resume blocks
+// with the fewest posting terms are dropped.
+func (c *Client) fitPrompt(ctx context.Context, resumeText, jobText string, signals *scoring.Signals) (fitted, error) {
+	f := fitted{data: promptData(resumeText, jobText, signals)}
+	model := c.provider.Model()
+	limit := int(float64(c.contextWindow()-outputReserveTokens) * fitMargin)
+
+	total, err := c.promptTokens(f.data)
+	if err != nil {
+		return fitted{}, err
+	}
+	if total <= limit {
+		return f, nil
+	}
+
+	bare := f.data
+	bare.ResumeText, bare.JobText = "", ""
+	overhead, err := c.promptTokens(bare)
+	if err != nil {
+		return fitted{}, err
+	}
+	if signals != nil && overhead > limit/2 {
+		f.data.BM25Signals = formatSignalsCompact(&signals.BM25)
+		bare.BM25Signals = f.data.BM25Signals
+		if overhead, err = c.promptTokens(bare); err != nil {
+			return fitted{}, err
+		}
+		f.notes = append(f.notes, "BM25 signals were abbreviated to fit the model context; per-section coverage and top resume chunks were omitted.")
+	}
+
+	available := limit - overhead
+	if available < minTextTokens {
+		return fitted{}, &ReportError{
+			Kind:     ErrorKindInputTooLarge,
+			Provider: c.provider.Name(),
+			Err:      fmt.Errorf("context window of %d tokens leaves no room for the resume and job posting", c.contextWindow()),
+		}
+	}
+
+	rel := newRelevance(signals)
+	resumeTokens := EstimateTokens(model, resumeText)
+
+	jobBudget := available - resumeTokens
+	if min := int(float64(available) * jobShare); jobBudget < min {
+		jobBudget = min
+	}
+	if EstimateTokens(model, jobText) > jobBudget {
+		var note string
+		f.data.JobText, note = fitJob(model, jobText, jobBudget)
+		f.notes = append(f.notes, note)
+	}
+
+	resumeBudget := available - EstimateTokens(model, f.data.JobText)
+	if resumeTokens <= resumeBudget {
+		return f, nil
+	}
+
+	resume := resumeText
+	if float64(resumeTokens) > condenseRatio*float64(resumeBudget) {
+		condensed, calls, err := c.condenseResume(ctx, resumeText, resumeBudget, rel)
+		f.calls = append(f.calls, calls...)
+		if err != nil {
+			return f, err
+		}
+		resume = condensed
+		f.notes = append(f.notes, fmt.Sprintf("The resume (~%d tokens) was too long for the model context and was condensed before analysis; evidence quotes that are not verbatim in the original resume were dropped.", resumeTokens))
+	}
+
+	if EstimateTokens(model, resume) > resumeBudget {
+		var note string
+		resume, note = truncateResume(model, resume, resumeBudget, rel)
+		f.notes = append(f.notes, note)
+	}
+	f.data.ResumeText = resume
+	return f, nil
+}
+
+// jobGroupPriority orders posting lines for fitJob; lower is kept first.
+var jobGroupPriority = map[jobpost.Group]int{
+	jobpost.GroupRequired:         0,
+	jobpost.GroupPreferred:        1,
+	jobpost.GroupResponsibilities: 2,
+	jobpost.GroupGeneral:          3,
+	jobpost.GroupBoilerplate:      4,
+}
+
+// fitJob keeps posting lines by requirement group until the budget is
+// spent and returns them in their original order.
+func fitJob(model, jobText string, budget int) (string, string) {
+	lines := jobpost.Parse(jobText).Lines
+	order := make([]int, len(lines))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return jobGroupPriority[lines[order[a]].Group] < jobGroupPriority[lines[order[b]].Group]
+	})
+
+	keep := make([]bool, len(lines))
+	used, kept := 0, 0
+	for _, i := range order {
+		n := EstimateTokens(model, lines[i].Text) + 1
+		if used+n > budget {
+			continue
+		}
+		keep[i] = true
+		used += n
+		kept++
+	}
+
+	var b strings.Builder
+	heading := ""
+	for i, l := range lines {
+		if !keep[i] {
+			continue
+		}
+		if l.Heading != "" && l.Heading != heading {
+			heading = l.Heading
+			b.WriteString(heading)
+			b.WriteString("\n")
+		}
+		b.WriteString(l.Text)
+		b.WriteString("\n")
+	}
+
+	note := fmt.Sprintf("The job posting was shortened to fit the model context: %d of %d lines kept, requirements first.", kept, len(lines))
+	return strings.TrimSpace(b.String()), note
+}
+
+// block is a paragraph or bullet of a resume section.
+type block struct {
+	text   string
+	bullet bool
+}
+
+func splitBlocks(text string) []block {
+	var out []block
+	var para []string
+	flush := func() {
+		if len(para) > 0 {
+			out = append(out, block{text: strings.Join(para, "\n")})
+			para = nil
+		}
+	}
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			flush()
+		case isBulletLine(trimmed):
+			flush()
+			out = append(out, block{text: trimmed, bullet: true})
+		default:
+			para = append(para, trimmed)
+		}
+	}
+	flush()
+	return out
+}
+
+// pinned sections are short and always worth keeping whole.
+var pinnedSections = map[sections.Kind]bool{
+	sections.KindHeader:  true,
+	sections.KindSummary: true,
+	sections.KindSkills:  true,
+}
+
+// truncateResume keeps headings, contact/summary/skills sections and
+// position lines (titles, employers, dates), then the bullets with the most
+// relevant posting terms, and marks where content was dropped.
+func truncateResume(model, resumeText string, budget int, rel relevance) (string, string) {
+	type candidate struct {
+		section, block int
+		tokens         int
+		score          float64
+	}
+
+	secs := sections.Detect(resumeText)
+	blocks := make([][]block, len(secs))
+	var cands []candidate
+	used, total := 0, 0
+	for si, sec := range secs {
+		used += EstimateTokens(model, sec.Heading) + 1
+		blocks[si] = splitBlocks(sec.Text)
+		for bi, b := range blocks[si] {
+			score := rel.score(b.text)
+			if pinnedSections[sec.Kind] || !b.bullet {
+				score = 1e9 // keep unless nothing else fits
+			}
+			cands = append(cands, candidate{section: si, block: bi, tokens: EstimateTokens(model, b.text) + 1, score: score})
+			total++
+		}
+	}
+
+	// Most relevant first; earlier (usually more recent) content breaks ties
+	sort.SliceStable(cands, func(i, j int) bool { return cands[i].score > cands[j].score })
+
+	keep := make(map[[2]int]bool)
+	for _, cd := range cands {
+		if used+cd.tokens > budget {
+			continue
+		}
+		keep[[2]int{cd.section, cd.block}] = true
+		used += cd.tokens
+	}
+
+	var b strings.Builder
+	var trimmedSections []string
+	for si, sec := range secs {
+		if sec.Heading != "" {
+			b.WriteString(sec.Heading)
+			b.WriteString("\n")
+		}
+		omitted := 0
+		for bi, blk := range blocks[si] {
+			if !keep[[2]int{si, bi}] {
+				omitted++
+				continue
+			}
+			if omitted > 0 {
+				fmt.Fprintf(&b, "[... %d omitted ...]\n", omitted)
+				omitted = 0
+			}
+			b.WriteString(blk.text)
+			b.WriteString("\n")
+		}
+		if omitted > 0 {
+			fmt.Fprintf(&b, "[... %d omitted ...]\n", omitted)
+		}
+		for bi := range blocks[si] {
+			if !keep[[2]int{si, bi}] {
+				trimmedSections = appendUnique(trimmedSections, string(sec.Kind))
+				break
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	note := fmt.Sprintf("The resume was shortened to fit the model context: %d of %d blocks kept; content with the fewest job posting terms was omitted from %s.",
+		len(keep), total, strings.Join(trimmedSections, ", "))
+	return strings.TrimSpace(b.String()), note
+}
+
+// condenseResume is the map step for very long resumes: each section, split
+// further when it is larger than one request allows, is shortened by the
+// LLM in proportion to its size. The results are joined in order.
+func (c *Client) condenseResume(ctx context.Context, resumeText string, budget int, rel relevance) (string, []CallUsage, error) {
+	if c.condense == nil {
+		return resumeText, nil, nil
+	}
+	model := c.provider.Model()
+	total := EstimateTokens(model, resumeText)
+	pieceLimit := int(float64(c.contextWindow()-outputReserveTokens)*fitMargin) / 2
+	terms := strings.Join(rel.topTerms(40), ", ")
+
+	var (
+		out   strings.Builder
+		calls []CallUsage
+	)
+	for _, sec := range sections.Detect(resumeText) {
+		if sec.Heading != "" {
+			out.WriteString(sec.Heading)
+			out.WriteString("\n")
+		}
+		for _, piece := range splitPieces(model, sec.Text, pieceLimit) {
+			tokens := EstimateTokens(model, piece)
+			target := budget * tokens / total
+			if target < 20 {
+				target = 20
+			}
+			if tokens <= target {
+				out.WriteString(piece)
+				out.WriteString("\n")
+				continue
+			}
+
+			system, user, err := c.condense.Render(CondenseData{
+				Excerpt:     piece,
+				TargetWords: target * 3 / 4,
+				Terms:       terms,
+			})
+			if err != nil {
+				return "", calls, err
+			}
+			resp, call, err := c.complete(ctx, len(calls)+1, CompletionRequest{System: system, Prompt: user, Temperature: c.temperature})
+			calls = append(calls, call)
+			if err != nil {
+				return "", calls, &ReportError{
+					Kind:     ErrorKindProvider,
+					Provider: c.provider.Name(),
+					Attempts: 1,
+					Calls:    calls,
+					Err:      fmt.Errorf("condense resume: %w", err),
+				}
+			}
+			out.WriteString(strings.TrimSpace(resp.Content))
+			out.WriteString("\n")
+		}
+		out.WriteString("\n")
+	}
+	return strings.TrimSpace(out.String()), calls, nil
+}
+
+// splitPieces cuts text at block boundaries into pieces of at most limit
+// tokens (a single oversized block stays whole).
+func splitPieces(model, text string, limit int) []string {
+	var pieces []string
+	var current []string
+	used := 0
+	for _, b := range splitBlocks(text) {
+		n := EstimateTokens(model, b.text) + 1
+		if used+n > limit && len(current) > 0 {
+			pieces = append(pieces, strings.Join(current, "\n"))
+			current, used = nil, 0
+		}
+		current = append(current, b.text)
+		used += n
+	}
+	if len(current) > 0 {
+		pieces = append(pieces, strings.Join(current, "\n"))
+	}
+	return pieces
+}
+
+// relevance scores resume text by the posting terms it contains, each
+// weighted by its BM25 IDF and requirement group weight.
+type relevance struct {
+	terms []weightedTerm
+}
+
+type weightedTerm struct {
+	pattern *regexp.Regexp
+	surface string
+	weight  float64
+}
+
+func newRelevance(signals *scoring.Signals) relevance {
+	if signals == nil {
+		return relevance{}
+	}
+
+	weights := make(map[string]float64)
+	add := func(surface string, w float64) {
+		surface = strings.ToLower(strings.TrimSpace(surface))
+		if surface != "" && w > weights[surface] {
+			weights[surface] = w
+		}
+	}
+	for _, t := range signals.BM25.Terms {
+		add(t.Surface, t.IDF*t.Weight)
+	}
+	for _, s := range signals.BM25.Skills {
+		w := weights[strings.ToLower(s.Name)]
+		for _, alias := range append(s.ResumeAliases, s.JobAliases...) {
+			add(alias, w)
+		}
+	}
+
+	var r relevance
+	for surface, w := range weights {
+		r.terms = append(r.terms, weightedTerm{
+			pattern: regexp.MustCompile(`(?i)(?:^|[^\pL\pN])` + regexp.QuoteMeta(surface) + `(?:$|[^\pL\pN+#])`),
+			surface: surface,
+			weight:  w,
+		})
+	}
+	sort.Slice(r.terms, func(i, j int) bool {
+		if r.terms[i].weight != r.terms[j].weight {
+			return r.terms[i].weight > r.terms[j].weight
+		}
+		return r.terms[i].surface < r.terms[j].surface
+	})
+	return r
+}
+
+func (r relevance) score(text string) float64 {
+	score := 0.0
+	for _, t := range r.terms {
+		if t.pattern.MatchString(text) {
+			score += t.weight
+		}
+	}
+	return score
+}
+
+func (r relevance) topTerms(n int) []string {
+	out := make([]string, 0, n)
+	for _, t := range r.terms {
+		if len(out) == n {
+			break
+		}
+		out = append(out, t.surface)
+	}
+	return out
+}
+
+// formatSignalsCompact is formatSignals without the per-section and
+// per-chunk tables, for small context windows.
+func formatSignalsCompact(s *bm25.Signals) string {
+	compact := *s
+	compact.Sections = nil
+	compact.TopDocuments = nil
+	return formatSignals(&compact)
+}
+
+func appendUnique(list []string, v string) []string {
+	for _, s := range list {
+		if s == v {
+			return list
+		}
+	}
+	return append(list, v)
+}
@@ -0,0 +1,91 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/option"
+	"github.com/openai/openai-go/shared"
+)
+
+// OpenAIProvider talks to the OpenAI chat completions API or any server
+// that implements it (Ollama, vLLM, llama.cpp server).
+type OpenAIProvider struct {
+	client openai.Client
+	name   string
+	model  string
+}
+
+// NewOpenAIProvider creates an OpenAIProvider. An empty baseURL uses the
+// OpenAI API; local servers usually accept any or no API key.
+func NewOpenAIProvider(name, apiKey, baseURL, model string) *OpenAIProvider {
+	opts := []option.RequestOption{option.WithAPIKey(apiKey)}
+	if baseURL != "" {
+		opts = append(opts, option.WithBaseURL(baseURL))
+	}
+
+	return &OpenAIProvider{
+		client: openai.NewClient(opts...),
+		name:   name,
+		model:  model,
+	}
+}
+
+func (p *OpenAIProvider) Name() string  { return p.name }
+func (p *OpenAIProvider) Model() string { return p.model }
+
+func (p *OpenAIProvider) Complete(ctx context.Context, req CompletionRequest) (Completion, error) {
+	params := openai.ChatCompletionNewParams{
+		Model: p.model,
+		Messages: []openai.ChatCompletionMessageParamUnion{
+			openai.SystemMessage(req.System),
+			openai.UserMessage(req.Prompt),
+		},
+	}
+	if req.Temperature != nil {
+		params.Temperature = openai.Float(*req.Temperature)
+	}
+	switch {
+	case req.Schema != nil:
+		// Not strict: strict mode rejects keywords such as minimum/minItems,
+		// and the reply is validated locally anyway.
+		params.ResponseFormat = openai.ChatCompletionNewParamsResponseFormatUnion{
+			OfJSONSchema: &shared.ResponseFormatJSONSchemaParam{
+				JSONSchema: shared.ResponseFormatJSONSchemaJSONSchemaParam{
+					Name:   req.SchemaName,
+					Strict: openai.Bool(false),
+					Schema: req.Schema,
+				},
+			},
+		}
+	case req.JSON:
+		params.ResponseFormat = openai.ChatCompletionNewParamsResponseFormatUnion{
+			OfJSONObject: func() *shared.ResponseFormatJSONObjectParam {
+				p := shared.NewResponseFormatJSONObjectParam()
+				return &p
+			}(),
+		}
+	}
+
+	resp, err := p.client.Chat.Completions.New(ctx, params)
+	if err != nil {
+		var apiErr *openai.Error
+		if errors.As(err, &apiErr) {
+			return Completion{}, &APIError{Provider: p.name, StatusCode: apiErr.StatusCode, Message: apiErr.Message}
+		}
+		return Completion{}, fmt.Errorf("%s API error: %w", p.name, err)
+	}
+
+	// Usage is billed even when the reply is empty
+	out := Completion{
+		InputTokens:  int(resp.Usage.PromptTokens),
+		OutputTokens: int(resp.Usage.CompletionTokens),
+	}
+	if len(resp.Choices) == 0 {
+		return out, fmt.Errorf("no choices in %s response", p.name)
+	}
+	out.Content = resp.Choices[0].Message.Content
+	return out, nil
+}
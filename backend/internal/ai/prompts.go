@@ -0,0 +1,174 @@
+package ai
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+//go:embed prompts/*.tmpl
+var promptFiles embed.FS
+
+// PromptRunReport is the prompt used by GenerateRunReport.
+const PromptRunReport = "run_report"
+
+// Prompt is one versioned prompt template. Each file defines a "system"
+// and a "user" template, and optionally "schema_version" (the report schema
+// the prompt asks for, 1 when absent); files are named <name>.<version>.tmpl.
+type Prompt struct {
+	Name          string
+	Version       string
+	SchemaVersion int
+	tmpl          *template.Template
+}
+
+// ID identifies the prompt in stored reports, e.g. "run_report@v1".
+func (p *Prompt) ID() string {
+	return p.Name + "@" + p.Version
+}
+
+// PromptData is the input to a prompt template. Signal sections are
+// pre-rendered JSON and empty when scoring failed.
+type PromptData struct {
+	ResumeText        string
+	JobText           string
+	BM25Signals       string
+	ExperienceSignals string
+}
+
+// Render executes the system and user templates with PromptData for run
+// report prompts, or the data type of the other prompts (CondenseData).
+func (p *Prompt) Render(data any) (system, user string, err error) {
+	var b bytes.Buffer
+	if err := p.tmpl.ExecuteTemplate(&b, "system", data); err != nil {
+		return "", "", fmt.Errorf("render prompt %s: %w", p.ID(), err)
+	}
+	system = b.String()
+
+	b.Reset()
+	if err := p.tmpl.ExecuteTemplate(&b, "user", data); err != nil {
+		return "", "", fmt.Errorf("render prompt %s: %w", p.ID(), err)
+	}
+	return system, b.String(), nil
+}
+
+// PromptRegistry resolves prompts by name and version. Old versions stay
+// in the registry so stored reports can always be traced to their prompt.
+type PromptRegistry struct {
+	prompts map[string]map[string]*Prompt
+	latest  map[string]string
+}
+
+// LoadPrompts parses the embedded prompt templates.
+func LoadPrompts() (*PromptRegistry, error) {
+	files, err := fs.Glob(promptFiles, "prompts/*.tmpl")
+	if err != nil {
+		return nil, err
+	}
+
+	r := &PromptRegistry{
+		prompts: make(map[string]map[string]*Prompt),
+		latest:  make(map[string]string),
+	}
+	for _, file := range files {
+		name, version, ok := strings.Cut(strings.TrimSuffix(path.Base(file), ".tmpl"), ".")
+		if !ok || !validVersion(version) {
+			return nil, fmt.Errorf("prompt file %s: want <name>.v<N>.tmpl", file)
+		}
+
+		tmpl, err := template.ParseFS(promptFiles, file)
+		if err != nil {
+			return nil, fmt.Errorf("parse prompt %s: %w", file, err)
+		}
+		for _, part := range []string{"system", "user"} {
+			if tmpl.Lookup(part) == nil {
+				return nil, fmt.Errorf("prompt %s: missing %q template", file, part)
+			}
+		}
+
+		schemaVersion := SchemaV1
+		if t := tmpl.Lookup("schema_version"); t != nil {
+			var b bytes.Buffer
+			if err := t.Execute(&b, nil); err != nil {
+				return nil, fmt.Errorf("prompt %s: %w", file, err)
+			}
+			schemaVersion, err = strconv.Atoi(strings.TrimSpace(b.String()))
+			if err != nil {
+				return nil, fmt.Errorf("prompt %s: bad schema_version: %w", file, err)
+			}
+			if _, err := reportSchema(schemaVersion); err != nil {
+				return nil, fmt.Errorf("prompt %s: %w", file, err)
+			}
+		}
+
+		if r.prompts[name] == nil {
+			r.prompts[name] = make(map[string]*Prompt)
+		}
+		r.prompts[name][version] = &Prompt{Name: name, Version: version, SchemaVersion: schemaVersion, tmpl: tmpl}
+		if versionNumber(version) > versionNumber(r.latest[name]) {
+			r.latest[name] = version
+		}
+	}
+	return r, nil
+}
+
+// Get returns a prompt by name and version; an empty version means the
+// latest one.
+func (r *PromptRegistry) Get(name, version string) (*Prompt, error) {
+	versions, ok := r.prompts[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown prompt %q", name)
+	}
+	if version == "" {
+		version = r.latest[name]
+	}
+	p, ok := versions[version]
+	if !ok {
+		return nil, fmt.Errorf("unknown version %q of prompt %q", version, name)
+	}
+	return p, nil
+}
+
+// Latest returns the newest version of a prompt that produces the given
+// report schema version.
+func (r *PromptRegistry) Latest(name string, schemaVersion int) (*Prompt, error) {
+	var best *Prompt
+	for _, p := range r.prompts[name] {
+		if p.SchemaVersion == schemaVersion && (best == nil || versionNumber(p.Version) > versionNumber(best.Version)) {
+			best = p
+		}
+	}
+	if best == nil {
+		return nil, fmt.Errorf("no version of prompt %q produces schema v%d", name, schemaVersion)
+	}
+	return best, nil
+}
+
+// Versions lists the versions of a prompt, oldest first.
+func (r *PromptRegistry) Versions(name string) []string {
+	out := make([]string, 0, len(r.prompts[name]))
+	for v := range r.prompts[name] {
+		out = append(out, v)
+	}
+	sort.Slice(out, func(i, j int) bool { return versionNumber(out[i]) < versionNumber(out[j]) })
+	return out
+}
+
+func validVersion(v string) bool {
+	return versionNumber(v) > 0
+}
+
+// versionNumber parses "v3" as 3; anything else is 0.
+func versionNumber(v string) int {
+	n, err := strconv.Atoi(strings.TrimPrefix(v, "v"))
+	if err != nil || !strings.HasPrefix(v, "v") {
+		return 0
+	}
+	return n
+}
@@ -0,0 +1,107 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Provider names accepted by NewProvider.
+const (
+	ProviderOpenAI           = "openai"
+	ProviderOpenAICompatible = "openai-compatible"
+	ProviderAnthropic        = "anthropic"
+	ProviderFake             = "fake"
+)
+
+// Provider sends a single-turn chat request to an LLM vendor and returns the
+// text reply. A failed call still reports the tokens the vendor billed, if
+// any, in the returned Completion.
+type Provider interface {
+	Name() string
+	Model() string
+	Complete(ctx context.Context, req CompletionRequest) (Completion, error)
+}
+
+// CompletionRequest is a vendor-neutral chat request. JSON asks the
+// provider to constrain the reply to a JSON object; Schema additionally
+// requests structured output where the provider supports it. A nil
+// Temperature uses the provider default.
+type CompletionRequest struct {
+	System      string
+	Prompt      string
+	JSON        bool
+	Schema      *Schema
+	SchemaName  string
+	Temperature *float64
+}
+
+// Completion is the text reply and token usage reported by the provider.
+type Completion struct {
+	Content      string
+	InputTokens  int
+	OutputTokens int
+}
+
+// CallUsage is one provider call made while generating a report, kept so
+// callers can account for tokens and cost, including failed attempts.
+type CallUsage struct {
+	Attempt      int
+	Provider     string
+	Model        string
+	InputTokens  int
+	OutputTokens int
+	Latency      time.Duration
+	Err          error
+}
+
+// ProviderConfig selects and configures a Provider.
+type ProviderConfig struct {
+	Name    string
+	APIKey  string
+	BaseURL string
+	Model   string
+}
+
+// NewProvider creates the provider named in cfg.
+func NewProvider(cfg ProviderConfig) (Provider, error) {
+	if cfg.Model == "" {
+		return nil, fmt.Errorf("LLM model is required")
+	}
+
+	switch cfg.Name {
+	case ProviderOpenAI:
+		if cfg.APIKey == "" {
+			return nil, fmt.Errorf("OPENAI_API_KEY is required")
+		}
+		return NewOpenAIProvider(ProviderOpenAI, cfg.APIKey, cfg.BaseURL, cfg.Model), nil
+	case ProviderOpenAICompatible:
+		if cfg.BaseURL == "" {
+			return nil, fmt.Errorf("LLM_BASE_URL is required for %s", ProviderOpenAICompatible)
+		}
+		return NewOpenAIProvider(ProviderOpenAICompatible, cfg.APIKey, cfg.BaseURL, cfg.Model), nil
+	case ProviderAnthropic:
+		if cfg.APIKey == "" {
+			return nil, fmt.Errorf("ANTHROPIC_API_KEY is required")
+		}
+		return NewAnthropicProvider(cfg.APIKey, cfg.BaseURL, cfg.Model), nil
+	case ProviderFake:
+		return NewFakeProvider(cfg.Model), nil
+	default:
+		return nil, fmt.Errorf("unknown LLM provider %q", cfg.Name)
+	}
+}
+
+// stripCodeFence removes a ```json fence some models wrap around JSON.
+func stripCodeFence(s string) string {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "```") {
+		return s
+	}
+	s = strings.TrimPrefix(s, "```")
+	if i := strings.Index(s, "\n"); i >= 0 {
+		s = s[i+1:]
+	}
+	return strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(s), "```"))
+}
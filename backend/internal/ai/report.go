@@ -0,0 +1,280 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"resume-tailor/internal/scoring/sections"
+)
+
+// Report schema versions. v1 is {score, notes} plus a list of free-text
+// changes; v2 adds sub-scores, skill evidence and structured change items.
+const (
+	SchemaV1 = 1
+	SchemaV2 = 2
+
+	LatestSchemaVersion = SchemaV2
+)
+
+// Report is a generated report serialized in one schema version, ready to
+// be stored. CacheKey is set when the LLM client has a cache; CacheHit
+// when the reply came from it. Calls lists the provider calls made, empty
+// for cache hits and deterministic reports.
+type Report struct {
+	SchemaVersion int
+	ATSReport     json.RawMessage
+	ChangePlan    json.RawMessage
+
+	CacheKey string
+	CacheHit bool
+	Calls    []CallUsage
+}
+
+func newReport(version int, atsReport, changePlan any) (Report, error) {
+	ats, err := json.Marshal(atsReport)
+	if err != nil {
+		return Report{}, fmt.Errorf("failed to marshal ATS report: %w", err)
+	}
+	plan, err := json.Marshal(changePlan)
+	if err != nil {
+		return Report{}, fmt.Errorf("failed to marshal change plan: %w", err)
+	}
+	return Report{SchemaVersion: version, ATSReport: ats, ChangePlan: plan}, nil
+}
+
+// SubScores break the overall score down; each is between 0 and 1.
+type SubScores struct {
+	KeywordCoverage float64 `json:"keyword_coverage"`
+	ExperienceFit   float64 `json:"experience_fit"`
+	Formatting      float64 `json:"formatting"`
+	Impact          float64 `json:"impact"`
+}
+
+// EvidenceSpan is a verbatim quote from the resume. Start and End are byte
+// offsets into the resume text.
+type EvidenceSpan struct {
+	Section string `json:"section"`
+	Quote   string `json:"quote"`
+	Start   int    `json:"start"`
+	End     int    `json:"end"`
+}
+
+// SkillEvidence is a posting skill found in the resume.
+type SkillEvidence struct {
+	Skill    string         `json:"skill"`
+	Evidence []EvidenceSpan `json:"evidence"`
+}
+
+// MissingSkill is a posting skill the resume does not show.
+type MissingSkill struct {
+	Skill      string `json:"skill"`
+	Importance string `json:"importance"`
+	Note       string `json:"note,omitempty"`
+}
+
+// ATSReportV2 is the v2 ATS report.
+type ATSReportV2 struct {
+	SchemaVersion int             `json:"schema_version"`
+	Score         float64         `json:"score"`
+	SubScores     SubScores       `json:"sub_scores"`
+	MatchedSkills []SkillEvidence `json:"matched_skills"`
+	MissingSkills []MissingSkill  `json:"missing_skills"`
+	Notes         []string        `json:"notes"`
+}
+
+// ChangeItem is one concrete edit. Terms are the posting (BM25) terms the
+// change is meant to cover.
+type ChangeItem struct {
+	ID            string   `json:"id"`
+	Priority      string   `json:"priority"`
+	Category      string   `json:"category"`
+	TargetSection string   `json:"target_section"`
+	OriginalText  string   `json:"original_text"`
+	SuggestedText string   `json:"suggested_text"`
+	Rationale     string   `json:"rationale"`
+	Terms         []string `json:"terms"`
+}
+
+// ChangePlanV2 is the v2 change plan.
+type ChangePlanV2 struct {
+	SchemaVersion int          `json:"schema_version"`
+	Items         []ChangeItem `json:"items"`
+}
+
+// ReportResponseV2 is the JSON structure expected from the LLM for v2.
+type ReportResponseV2 struct {
+	ATSReport  ATSReportV2  `json:"ats_report"`
+	ChangePlan ChangePlanV2 `json:"change_plan"`
+}
+
+// Allowed values for change item fields.
+var (
+	Priorities       = []string{"high", "medium", "low"}
+	ChangeCategories = []string{"keywords", "experience", "impact", "formatting", "summary", "skills"}
+	SkillImportances = []string{"required", "preferred"}
+	ResumeSections   = []string{
+		string(sections.KindHeader), string(sections.KindSummary), string(sections.KindExperience),
+		string(sections.KindEducation), string(sections.KindSkills), string(sections.KindProjects),
+		string(sections.KindCertifications), string(sections.KindOther),
+	}
+)
+
+func unitScore(description string) *Schema {
+	return &Schema{Type: "number", Description: description, Minimum: ptr(0.0), Maximum: ptr(1.0)}
+}
+
+func nonEmptyString() *Schema {
+	return &Schema{Type: "string", MinLength: ptr(1)}
+}
+
+// ReportSchemaV2 describes ReportResponseV2. schema_version and evidence
+// offsets are optional here because the client fills them in.
+var ReportSchemaV2 = &Schema{
+	Type:                 "object",
+	Required:             []string{"ats_report", "change_plan"},
+	AdditionalProperties: ptr(false),
+	Properties: map[string]*Schema{
+		"ats_report": {
+			Type:                 "object",
+			Required:             []string{"score", "sub_scores", "matched_skills", "missing_skills", "notes"},
+			AdditionalProperties: ptr(false),
+			Properties: map[string]*Schema{
+				"schema_version": {Type: "integer"},
+				"score":          unitScore("Overall ATS compatibility score"),
+				"sub_scores": {
+					Type:                 "object",
+					Required:             []string{"keyword_coverage", "experience_fit", "formatting", "impact"},
+					AdditionalProperties: ptr(false),
+					Properties: map[string]*Schema{
+						"keyword_coverage": unitScore("How well posting terms are covered"),
+						"experience_fit":   unitScore("Years and seniority against the posting"),
+						"formatting":       unitScore("ATS-friendly structure and section headings"),
+						"impact":           unitScore("Quantified, outcome-focused bullets"),
+					},
+				},
+				"matched_skills": {
+					Type: "array",
+					Items: &Schema{
+						Type:                 "object",
+						Required:             []string{"skill", "evidence"},
+						AdditionalProperties: ptr(false),
+						Properties: map[string]*Schema{
+							"skill": nonEmptyString(),
+							"evidence": {
+								Type: "array",
+								Items: &Schema{
+									Type:                 "object",
+									Required:             []string{"section", "quote"},
+									AdditionalProperties: ptr(false),
+									Properties: map[string]*Schema{
+										"section": {Type: "string", Enum: ResumeSections},
+										"quote":   {Type: "string", MinLength: ptr(1), Description: "Verbatim text copied from the resume"},
+										"start":   {Type: "integer"},
+										"end":     {Type: "integer"},
+									},
+								},
+							},
+						},
+					},
+				},
+				"missing_skills": {
+					Type: "array",
+					Items: &Schema{
+						Type:                 "object",
+						Required:             []string{"skill", "importance"},
+						AdditionalProperties: ptr(false),
+						Properties: map[string]*Schema{
+							"skill":      nonEmptyString(),
+							"importance": {Type: "string", Enum: SkillImportances},
+							"note":       {Type: "string"},
+						},
+					},
+				},
+				"notes": {Type: "array", Items: nonEmptyString()},
+			},
+		},
+		"change_plan": {
+			Type:                 "object",
+			Required:             []string{"items"},
+			AdditionalProperties: ptr(false),
+			Properties: map[string]*Schema{
+				"schema_version": {Type: "integer"},
+				"items": {
+					Type:     "array",
+					MinItems: ptr(1),
+					Items: &Schema{
+						Type:                 "object",
+						Required:             []string{"id", "priority", "category", "target_section", "original_text", "suggested_text", "rationale", "terms"},
+						AdditionalProperties: ptr(false),
+						Properties: map[string]*Schema{
+							"id":             nonEmptyString(),
+							"priority":       {Type: "string", Enum: Priorities},
+							"category":       {Type: "string", Enum: ChangeCategories},
+							"target_section": {Type: "string", Enum: ResumeSections},
+							"original_text":  {Type: "string", Description: "Resume text to replace; empty for additions"},
+							"suggested_text": nonEmptyString(),
+							"rationale":      nonEmptyString(),
+							"terms":          {Type: "array", Items: nonEmptyString()},
+						},
+					},
+				},
+			},
+		},
+	},
+}
+
+// reportSchema returns the output schema for a schema version.
+func reportSchema(version int) (*Schema, error) {
+	switch version {
+	case SchemaV1:
+		return ReportSchema, nil
+	case SchemaV2:
+		return ReportSchemaV2, nil
+	default:
+		return nil, fmt.Errorf("unsupported report schema version %d", version)
+	}
+}
+
+// decodeReport turns validated LLM output into a stored Report. For v2,
+// evidence quotes are located in the resume and dropped when they do not
+// appear there verbatim.
+func decodeReport(version int, content []byte, resumeText string) (Report, error) {
+	switch version {
+	case SchemaV1:
+		var resp ReportResponse
+		if err := json.Unmarshal(content, &resp); err != nil {
+			return Report{}, err
+		}
+		return newReport(SchemaV1, resp.ATSReport, resp.ChangePlan)
+	case SchemaV2:
+		var resp ReportResponseV2
+		if err := json.Unmarshal(content, &resp); err != nil {
+			return Report{}, err
+		}
+		resp.ATSReport.SchemaVersion = SchemaV2
+		resp.ChangePlan.SchemaVersion = SchemaV2
+		for i := range resp.ATSReport.MatchedSkills {
+			s := &resp.ATSReport.MatchedSkills[i]
+			s.Evidence = locateEvidence(resumeText, s.Evidence)
+		}
+		return newReport(SchemaV2, resp.ATSReport, resp.ChangePlan)
+	default:
+		return Report{}, fmt.Errorf("unsupported report schema version %d", version)
+	}
+}
+
+func locateEvidence(resumeText string, spans []EvidenceSpan) []EvidenceSpan {
+	out := make([]EvidenceSpan, 0, len(spans))
+	for _, span := range spans {
+		quote := strings.TrimSpace(span.Quote)
+		start := strings.Index(resumeText, quote)
+		if quote == "" || start < 0 {
+			continue
+		}
+		span.Quote = quote
+		span.Start, span.End = start, start+len(quote)
+		out = append(out, span)
+	}
+	return out
+}
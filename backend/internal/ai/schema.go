@@ -0,0 +1,210 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Schema is the subset of JSON Schema used to describe LLM output. It
+// marshals to standard JSON Schema so it can be sent to providers that
+// support structured output, and validates decoded JSON locally.
+type Schema struct {
+	Type                 string             `json:"type"`
+	Description          string             `json:"description,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+}
+
+// ValidationError is one schema violation at a JSON path such as
+// "$.ats_report.score".
+type ValidationError struct {
+	Path    string
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	return e.Path + ": " + e.Message
+}
+
+// ValidateJSON decodes raw and validates it against the schema. A syntax
+// error is reported as a single violation at the root.
+func (s *Schema) ValidateJSON(raw []byte) []ValidationError {
+	var v any
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return []ValidationError{{Path: "$", Message: "invalid JSON: " + err.Error()}}
+	}
+	return s.Validate(v)
+}
+
+// Validate checks a value decoded by encoding/json into any.
+func (s *Schema) Validate(v any) []ValidationError {
+	var errs []ValidationError
+	s.validate("$", v, &errs)
+	return errs
+}
+
+func (s *Schema) validate(path string, v any, errs *[]ValidationError) {
+	fail := func(format string, args ...any) {
+		*errs = append(*errs, ValidationError{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	switch s.Type {
+	case "object":
+		obj, ok := v.(map[string]any)
+		if !ok {
+			fail("expected object, got %s", jsonType(v))
+			return
+		}
+		for _, name := range s.Required {
+			if _, ok := obj[name]; !ok {
+				fail("missing required property %q", name)
+			}
+		}
+		keys := make([]string, 0, len(obj))
+		for k := range obj {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			prop, ok := s.Properties[k]
+			if !ok {
+				if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+					fail("unexpected property %q", k)
+				}
+				continue
+			}
+			prop.validate(path+"."+k, obj[k], errs)
+		}
+
+	case "array":
+		arr, ok := v.([]any)
+		if !ok {
+			fail("expected array, got %s", jsonType(v))
+			return
+		}
+		if s.MinItems != nil && len(arr) < *s.MinItems {
+			fail("expected at least %d items, got %d", *s.MinItems, len(arr))
+		}
+		if s.Items != nil {
+			for i, item := range arr {
+				s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item, errs)
+			}
+		}
+
+	case "string":
+		str, ok := v.(string)
+		if !ok {
+			fail("expected string, got %s", jsonType(v))
+			return
+		}
+		if s.MinLength != nil && len(strings.TrimSpace(str)) < *s.MinLength {
+			fail("expected at least %d characters", *s.MinLength)
+		}
+		if len(s.Enum) > 0 && !contains(s.Enum, str) {
+			fail("expected one of %s, got %q", strings.Join(s.Enum, ", "), str)
+		}
+
+	case "number", "integer":
+		num, ok := v.(float64)
+		if !ok {
+			fail("expected %s, got %s", s.Type, jsonType(v))
+			return
+		}
+		if s.Type == "integer" && num != float64(int64(num)) {
+			fail("expected integer, got %v", num)
+		}
+		if s.Minimum != nil && num < *s.Minimum {
+			fail("must be >= %v, got %v", *s.Minimum, num)
+		}
+		if s.Maximum != nil && num > *s.Maximum {
+			fail("must be <= %v, got %v", *s.Maximum, num)
+		}
+
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			fail("expected boolean, got %s", jsonType(v))
+		}
+	}
+}
+
+func jsonType(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+func contains(list []string, v string) bool {
+	for _, s := range list {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// ReportSchema describes ReportResponse.
+var ReportSchema = &Schema{
+	Type:                 "object",
+	Required:             []string{"ats_report", "change_plan"},
+	AdditionalProperties: ptr(false),
+	Properties: map[string]*Schema{
+		"ats_report": {
+			Type:                 "object",
+			Required:             []string{"score", "notes"},
+			AdditionalProperties: ptr(false),
+			Properties: map[string]*Schema{
+				"score": {
+					Type:        "number",
+					Description: "ATS compatibility score between 0.0 and 1.0",
+					Minimum:     ptr(0.0),
+					Maximum:     ptr(1.0),
+				},
+				"notes": {
+					Type:        "array",
+					Description: "Notes explaining the score",
+					MinItems:    ptr(1),
+					Items:       &Schema{Type: "string", MinLength: ptr(1)},
+				},
+			},
+		},
+		"change_plan": {
+			Type:                 "object",
+			Required:             []string{"changes"},
+			AdditionalProperties: ptr(false),
+			Properties: map[string]*Schema{
+				"changes": {
+					Type:        "array",
+					Description: "Specific recommendations to tailor the resume",
+					MinItems:    ptr(1),
+					Items:       &Schema{Type: "string", MinLength: ptr(1)},
+				},
+			},
+		},
+	},
+}
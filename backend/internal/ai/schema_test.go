@@ -0,0 +1,117 @@
+package ai
+
+import (
+	"strings"
+	"testing"
+)
+
+const validV2 = `{
+  "ats_report": {
+    "score": 0.7,
+    "sub_scores": {"keyword_coverage": 0.6, "experience_fit": 0.8, "formatting": 0.7, "impact": 0.6},
+    "matched_skills": [{"skill": "Go", "evidence": [{"section": "skills", "quote": "Go"}]}],
+    "missing_skills": [{"skill": "Kubernetes", "importance": "required"}],
+    "notes": ["Strong Go background."]
+  },
+  "change_plan": {
+    "items": [{
+      "id": "c1", "priority": "high", "category": "keywords", "target_section": "skills",
+      "original_text": "", "suggested_text": "Add Kubernetes.", "rationale": "Required.", "terms": ["kubernetes"]
+    }]
+  }
+}`
+
+func TestValidateJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   *Schema
+		doc      string
+		wantPath string // empty means valid
+		wantMsg  string
+	}{
+		{
+			name:   "v1 valid",
+			schema: ReportSchema,
+			doc:    `{"ats_report": {"score": 0.5, "notes": ["ok"]}, "change_plan": {"changes": ["add Go"]}}`,
+		},
+		{
+			name:     "v1 missing field",
+			schema:   ReportSchema,
+			doc:      `{"ats_report": {"score": 0.5}, "change_plan": {"changes": ["add Go"]}}`,
+			wantPath: "$.ats_report",
+			wantMsg:  `missing required property "notes"`,
+		},
+		{
+			name:     "v1 wrong type",
+			schema:   ReportSchema,
+			doc:      `{"ats_report": {"score": "high", "notes": ["ok"]}, "change_plan": {"changes": ["add Go"]}}`,
+			wantPath: "$.ats_report.score",
+			wantMsg:  "expected number, got string",
+		},
+		{
+			name:     "v1 score out of range",
+			schema:   ReportSchema,
+			doc:      `{"ats_report": {"score": 1.5, "notes": ["ok"]}, "change_plan": {"changes": ["add Go"]}}`,
+			wantPath: "$.ats_report.score",
+			wantMsg:  "must be <= 1",
+		},
+		{
+			name:     "v1 invalid JSON",
+			schema:   ReportSchema,
+			doc:      `{"ats_report": {`,
+			wantPath: "$",
+			wantMsg:  "invalid JSON",
+		},
+		{
+			name:   "v2 valid",
+			schema: ReportSchemaV2,
+			doc:    validV2,
+		},
+		{
+			name:     "v2 missing field",
+			schema:   ReportSchemaV2,
+			doc:      strings.Replace(validV2, `"sub_scores": {"keyword_coverage": 0.6, `, `"sub_scores": {`, 1),
+			wantPath: "$.ats_report.sub_scores",
+			wantMsg:  `missing required property "keyword_coverage"`,
+		},
+		{
+			name:     "v2 wrong type",
+			schema:   ReportSchemaV2,
+			doc:      strings.Replace(validV2, `"terms": ["kubernetes"]`, `"terms": "kubernetes"`, 1),
+			wantPath: "$.change_plan.items[0].terms",
+			wantMsg:  "expected array, got string",
+		},
+		{
+			name:     "v2 score out of range",
+			schema:   ReportSchemaV2,
+			doc:      strings.Replace(validV2, `"impact": 0.6`, `"impact": -0.1`, 1),
+			wantPath: "$.ats_report.sub_scores.impact",
+			wantMsg:  "must be >= 0",
+		},
+		{
+			name:     "v2 value outside enum",
+			schema:   ReportSchemaV2,
+			doc:      strings.Replace(validV2, `"priority": "high"`, `"priority": "urgent"`, 1),
+			wantPath: "$.change_plan.items[0].priority",
+			wantMsg:  "expected one of high, medium, low",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := tt.schema.ValidateJSON([]byte(tt.doc))
+			if tt.wantPath == "" {
+				if len(errs) != 0 {
+					t.Fatalf("got %v, want no violations", errs)
+				}
+				return
+			}
+			if len(errs) != 1 {
+				t.Fatalf("got %d violations %v, want 1", len(errs), errs)
+			}
+			if errs[0].Path != tt.wantPath || !strings.Contains(errs[0].Message, tt.wantMsg) {
+				t.Errorf("got %v, want %s: %s", errs[0], tt.wantPath, tt.wantMsg)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package ai
+
+import (
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+// defaultContextWindow is assumed for models not listed below, which are
+// mostly small local models.
+const defaultContextWindow = 8192
+
+// contextWindows maps model name prefixes to their context size in tokens,
+// listed from most to least specific; the longest matching prefix wins.
+var contextWindows = []struct {
+	prefix string
+	tokens int
+}{
+	{"gpt-3.5-turbo", 16385},
+	{"gpt-4-turbo", 128000},
+	{"gpt-4.1", 1047576},
+	{"gpt-4o", 128000},
+	{"o4-mini", 200000},
+	{"o3", 200000},
+	{"o1", 200000},
+	{"claude", 200000},
+	{"llama3.1", 131072},
+	{"llama3.2", 131072},
+	{"llama3.3", 131072},
+	{"llama3", 8192},
+	{"qwen2.5", 32768},
+	{"mistral", 32768},
+}
+
+// ContextWindow returns the context size of a model in tokens.
+func ContextWindow(model string) int {
+	model = strings.ToLower(model)
+	best, window := "", defaultContextWindow
+	for _, w := range contextWindows {
+		if strings.HasPrefix(model, w.prefix) && len(w.prefix) > len(best) {
+			best, window = w.prefix, w.tokens
+		}
+	}
+	return window
+}
+
+// EstimateTokens approximates how many tokens the model's tokenizer
+// produces for text: about 4 characters per token for GPT-style BPE
+// vocabularies and 3.5 for Claude, but never fewer than one per word.
+// It errs high, which is the safe side for fitting a context window.
+func EstimateTokens(model, text string) int {
+	if text == "" {
+		return 0
+	}
+	perToken := 4.0
+	if strings.HasPrefix(strings.ToLower(model), "claude") {
+		perToken = 3.5
+	}
+	n := int(math.Ceil(float64(utf8.RuneCountInString(text)) / perToken))
+	if words := len(strings.Fields(text)); words > n {
+		n = words
+	}
+	return n
+}
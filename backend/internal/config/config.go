@@ -3,23 +3,99 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"time"
+
+	"resume-tailor/internal/jobs"
+	"resume-tailor/internal/usage"
 )
 
 type Config struct {
-	DatabaseURL  string
-	HTTPAddr     string
-	WorkerID     string
-	OpenAIAPIKey string
-	OpenAIModel  string
+	DatabaseURL string
+	HTTPAddr    string
+	WorkerID    string
+
+	// AdminToken guards the /v1/admin routes (X-Admin-Token header); they
+	// are not mounted when it is empty.
+	AdminToken string
+
+	// IdempotencyKeyTTL is how long an Idempotency-Key on POST /v1/runs and
+	// POST /v1/resumes is remembered (default 24h).
+	IdempotencyKeyTTL time.Duration
+
+	// LLMProvider is openai (default), openai-compatible (Ollama, vLLM,
+	// llama.cpp server at LLMBaseURL), anthropic or fake (canned offline
+	// responses for local development). LLMAPIKey falls back to
+	// OPENAI_API_KEY or ANTHROPIC_API_KEY depending on the provider.
+	LLMProvider string
+	LLMBaseURL  string
+	LLMAPIKey   string
+	LLMModel    string
+
+	// LLMTemperature is the sampling temperature; nil uses the provider
+	// default.
+	LLMTemperature *float64
+
+	// LLMCacheTTL is how long identical LLM requests are answered from the
+	// cache (default 7 days); 0 disables the cache.
+	LLMCacheTTL time.Duration
+
+	// LLMContextTokens overrides the model's context window used to fit
+	// long resumes and postings into the prompt; 0 uses the built-in table.
+	LLMContextTokens int
+
+	// LLMPrice overrides the bundled per-model price list (USD per million
+	// tokens); set both LLM_PRICE_INPUT_PER_MTOK and
+	// LLM_PRICE_OUTPUT_PER_MTOK for models it does not know.
+	LLMPrice *usage.Price
+
+	// Monthly LLM spend limits in USD; 0 means unlimited. The user budget
+	// applies per subscription period (calendar month without one).
+	LLMUserMonthlyBudgetUSD float64
+	LLMMonthlyBudgetUSD     float64
+
+	// SkillsTaxonomyPath overrides the bundled skills taxonomy (JSON).
+	SkillsTaxonomyPath string
+
+	// ReportSchemaVersion selects the report JSON shape (1 or 2, default 2).
+	// PromptVersion pins the run report prompt (e.g. "v1") and must produce
+	// that schema; empty uses the latest prompt for the schema.
+	ReportSchemaVersion int
+	PromptVersion       string
+
+	// JobLease is how long a claimed job stays locked to its worker without
+	// a heartbeat (at least 3s); workers renew it every third of that while
+	// the job runs.
+	// Jobs whose lease expired are reaped every JobReapInterval.
+	JobLease        time.Duration
+	JobReapInterval time.Duration
+
+	// WorkerConcurrency is how many jobs a worker process runs at once. On
+	// shutdown, in-flight jobs get WorkerShutdownTimeout to finish before
+	// they are cancelled.
+	WorkerConcurrency     int
+	WorkerShutdownTimeout time.Duration
+
+	// ReportMode is "auto" (LLM when configured, deterministic otherwise or
+	// when the LLM call fails after its retries), "llm" or "deterministic".
+	ReportMode string
 }
 
 func Load() (Config, error) {
 	cfg := Config{
-		DatabaseURL:  os.Getenv("DATABASE_URL"),
-		HTTPAddr:     os.Getenv("HTTP_ADDR"),
-		WorkerID:     os.Getenv("WORKER_ID"),
-		OpenAIAPIKey: os.Getenv("OPENAI_API_KEY"),
-		OpenAIModel:  os.Getenv("OPENAI_MODEL"),
+		DatabaseURL: os.Getenv("DATABASE_URL"),
+		HTTPAddr:    os.Getenv("HTTP_ADDR"),
+		AdminToken:  os.Getenv("ADMIN_TOKEN"),
+		WorkerID:    os.Getenv("WORKER_ID"),
+
+		LLMProvider: os.Getenv("LLM_PROVIDER"),
+		LLMBaseURL:  os.Getenv("LLM_BASE_URL"),
+		LLMAPIKey:   os.Getenv("LLM_API_KEY"),
+		LLMModel:    os.Getenv("LLM_MODEL"),
+
+		SkillsTaxonomyPath: os.Getenv("SKILLS_TAXONOMY_PATH"),
+		PromptVersion:      os.Getenv("PROMPT_VERSION"),
+		ReportMode:         os.Getenv("REPORT_MODE"),
 	}
 
 	if cfg.DatabaseURL == "" {
@@ -34,9 +110,155 @@ func Load() (Config, error) {
 		cfg.WorkerID = "worker-1"
 	}
 
-	if cfg.OpenAIModel == "" {
-		cfg.OpenAIModel = "gpt-4o-mini"
+	switch cfg.LLMProvider {
+	case "", "openai":
+		cfg.LLMProvider = "openai"
+		if cfg.LLMAPIKey == "" {
+			cfg.LLMAPIKey = os.Getenv("OPENAI_API_KEY")
+		}
+		if cfg.LLMModel == "" {
+			cfg.LLMModel = os.Getenv("OPENAI_MODEL")
+		}
+		if cfg.LLMModel == "" {
+			cfg.LLMModel = "gpt-4o-mini"
+		}
+	case "openai-compatible":
+		if cfg.LLMBaseURL == "" {
+			return Config{}, fmt.Errorf("LLM_BASE_URL is required for openai-compatible provider")
+		}
+		if cfg.LLMModel == "" {
+			return Config{}, fmt.Errorf("LLM_MODEL is required for openai-compatible provider")
+		}
+	case "anthropic":
+		if cfg.LLMAPIKey == "" {
+			cfg.LLMAPIKey = os.Getenv("ANTHROPIC_API_KEY")
+		}
+		if cfg.LLMModel == "" {
+			cfg.LLMModel = "claude-3-5-haiku-latest"
+		}
+	case "fake":
+		if cfg.LLMModel == "" {
+			cfg.LLMModel = "fake"
+		}
+	default:
+		return Config{}, fmt.Errorf("LLM_PROVIDER must be openai, openai-compatible, anthropic or fake")
+	}
+
+	switch cfg.ReportMode {
+	case "":
+		cfg.ReportMode = "auto"
+	case "auto", "llm", "deterministic":
+	default:
+		return Config{}, fmt.Errorf("REPORT_MODE must be auto, llm or deterministic")
+	}
+
+	cfg.ReportSchemaVersion = 2
+	if v := os.Getenv("REPORT_SCHEMA_VERSION"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 2 {
+			return Config{}, fmt.Errorf("REPORT_SCHEMA_VERSION must be 1 or 2")
+		}
+		cfg.ReportSchemaVersion = n
+	}
+
+	if v := os.Getenv("LLM_TEMPERATURE"); v != "" {
+		t, err := strconv.ParseFloat(v, 64)
+		if err != nil || t < 0 || t > 2 {
+			return Config{}, fmt.Errorf("LLM_TEMPERATURE must be a number between 0 and 2")
+		}
+		cfg.LLMTemperature = &t
+	}
+
+	cfg.LLMCacheTTL = 7 * 24 * time.Hour
+	if v := os.Getenv("LLM_CACHE_TTL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			return Config{}, fmt.Errorf("LLM_CACHE_TTL must be a duration such as 168h, or 0 to disable")
+		}
+		cfg.LLMCacheTTL = d
+	}
+
+	if v := os.Getenv("LLM_CONTEXT_TOKENS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return Config{}, fmt.Errorf("LLM_CONTEXT_TOKENS must be a positive integer")
+		}
+		cfg.LLMContextTokens = n
+	}
+
+	inPrice, outPrice := os.Getenv("LLM_PRICE_INPUT_PER_MTOK"), os.Getenv("LLM_PRICE_OUTPUT_PER_MTOK")
+	if inPrice != "" || outPrice != "" {
+		in, errIn := strconv.ParseFloat(inPrice, 64)
+		out, errOut := strconv.ParseFloat(outPrice, 64)
+		if errIn != nil || errOut != nil || in < 0 || out < 0 {
+			return Config{}, fmt.Errorf("LLM_PRICE_INPUT_PER_MTOK and LLM_PRICE_OUTPUT_PER_MTOK must both be non-negative numbers")
+		}
+		cfg.LLMPrice = &usage.Price{InputPerMTok: in, OutputPerMTok: out}
+	}
+
+	var err error
+	if cfg.LLMUserMonthlyBudgetUSD, err = budget("LLM_USER_MONTHLY_BUDGET_USD"); err != nil {
+		return Config{}, err
+	}
+	if cfg.LLMMonthlyBudgetUSD, err = budget("LLM_MONTHLY_BUDGET_USD"); err != nil {
+		return Config{}, err
+	}
+
+	if cfg.IdempotencyKeyTTL, err = duration("IDEMPOTENCY_KEY_TTL", 24*time.Hour); err != nil {
+		return Config{}, err
+	}
+
+	if cfg.JobLease, err = duration("JOB_LEASE", jobs.DefaultLease); err != nil {
+		return Config{}, err
+	}
+	if cfg.JobLease < jobs.MinLease {
+		return Config{}, fmt.Errorf("JOB_LEASE must be at least %s", jobs.MinLease)
+	}
+	if cfg.JobReapInterval, err = duration("JOB_REAP_INTERVAL", 30*time.Second); err != nil {
+		return Config{}, err
+	}
+
+	cfg.WorkerConcurrency = 1
+	if v := os.Getenv("WORKER_CONCURRENCY"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return Config{}, fmt.Errorf("WORKER_CONCURRENCY must be a positive integer")
+		}
+		cfg.WorkerConcurrency = n
+	}
+	if cfg.WorkerShutdownTimeout, err = duration("WORKER_SHUTDOWN_TIMEOUT", 60*time.Second); err != nil {
+		return Config{}, err
 	}
 
 	return cfg, nil
 }
+
+func duration(name string, def time.Duration) (time.Duration, error) {
+	v := os.Getenv(name)
+	if v == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("%s must be a positive duration such as 30s or 15m", name)
+	}
+	return d, nil
+}
+
+func budget(name string) (float64, error) {
+	v := os.Getenv(name)
+	if v == "" {
+		return 0, nil
+	}
+	usd, err := strconv.ParseFloat(v, 64)
+	if err != nil || usd < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative amount in USD", name)
+	}
+	return usd, nil
+}
+
+// LLMEnabled reports whether enough is configured to call the LLM. Local
+// OpenAI-compatible servers and the fake provider need no API key.
+func (c Config) LLMEnabled() bool {
+	return c.LLMAPIKey != "" || c.LLMProvider == "openai-compatible" || c.LLMProvider == "fake"
+}
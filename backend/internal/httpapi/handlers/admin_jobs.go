@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"resume-tailor/internal/jobs"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type jobResponse struct {
+	ID             uuid.UUID       `json:"id"`
+	Type           string          `json:"type"`
+	RunID          *uuid.UUID      `json:"runId,omitempty"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	MaxAttempts    int             `json:"maxAttempts"`
+	LockedBy       *string         `json:"lockedBy,omitempty"`
+	LeaseExpiresAt *time.Time      `json:"leaseExpiresAt,omitempty"`
+	LastError      *string         `json:"lastError,omitempty"`
+	RunAfter       time.Time       `json:"runAfter"`
+	CreatedAt      time.Time       `json:"createdAt"`
+	UpdatedAt      time.Time       `json:"updatedAt"`
+}
+
+type attemptResponse struct {
+	Attempt    int       `json:"attempt"`
+	WorkerID   string    `json:"workerId"`
+	Outcome    string    `json:"outcome"`
+	Error      *string   `json:"error,omitempty"`
+	StartedAt  time.Time `json:"startedAt"`
+	FinishedAt time.Time `json:"finishedAt"`
+	DurationMs int64     `json:"durationMs"`
+}
+
+type jobDetailResponse struct {
+	Job      jobResponse       `json:"job"`
+	Attempts []attemptResponse `json:"attempts"`
+}
+
+// DeadJobsActionRequest selects dead jobs to requeue or discard. The
+// fields combine with AND; at least one must be set.
+type DeadJobsActionRequest struct {
+	IDs   []string `json:"ids"`
+	Type  string   `json:"type"`
+	Error string   `json:"error"`
+}
+
+type deadJobsActionResponse struct {
+	Count int         `json:"count"`
+	IDs   []uuid.UUID `json:"ids"`
+}
+
+func toJobResponse(j jobs.Job) jobResponse {
+	return jobResponse{
+		ID:             j.ID,
+		Type:           j.Type,
+		RunID:          j.RunID,
+		Payload:        j.Payload,
+		Status:         j.Status,
+		Attempts:       j.Attempts,
+		MaxAttempts:    j.MaxAttempts,
+		LockedBy:       j.LockedBy,
+		LeaseExpiresAt: j.LeaseExpiresAt,
+		LastError:      j.LastError,
+		RunAfter:       j.RunAfter,
+		CreatedAt:      j.CreatedAt,
+		UpdatedAt:      j.UpdatedAt,
+	}
+}
+
+// ListDeadJobsHandler lists failed jobs, filtered by ?type= and ?error=
+// (a substring of the last error).
+func ListDeadJobsHandler(adminSvc *jobs.AdminService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		limit, offset := 50, 0
+		if raw := r.URL.Query().Get("limit"); raw != "" {
+			v, err := strconv.Atoi(raw)
+			if err != nil || v <= 0 {
+				writeError(w, http.StatusBadRequest, "invalid limit")
+				return
+			}
+			limit = min(v, 500)
+		}
+		if raw := r.URL.Query().Get("offset"); raw != "" {
+			v, err := strconv.Atoi(raw)
+			if err != nil || v < 0 {
+				writeError(w, http.StatusBadRequest, "invalid offset")
+				return
+			}
+			offset = v
+		}
+
+		filter := jobs.DeadJobFilter{
+			Type:  r.URL.Query().Get("type"),
+			Error: r.URL.Query().Get("error"),
+		}
+		list, err := adminSvc.ListDeadJobs(r.Context(), filter, limit, offset)
+		if err != nil {
+			writeJobsError(w, err)
+			return
+		}
+
+		resp := make([]jobResponse, len(list))
+		for i, j := range list {
+			resp[i] = toJobResponse(j)
+		}
+		writeJSON(w, http.StatusOK, resp)
+	}
+}
+
+// GetJobHandler returns a job with its attempt history.
+func GetJobHandler(adminSvc *jobs.AdminService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		jobID, err := uuid.Parse(chi.URLParam(r, "jobID"))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid jobId")
+			return
+		}
+
+		detail, err := adminSvc.GetJob(r.Context(), jobID)
+		if err != nil {
+			writeJobsError(w, err)
+			return
+		}
+
+		resp := jobDetailResponse{
+			Job:      toJobResponse(detail.Job),
+			Attempts: make([]attemptResponse, len(detail.Attempts)),
+		}
+		for i, a := range detail.Attempts {
+			resp.Attempts[i] = attemptResponse{
+				Attempt:    a.Attempt,
+				WorkerID:   a.WorkerID,
+				Outcome:    a.Outcome,
+				Error:      a.Error,
+				StartedAt:  a.StartedAt,
+				FinishedAt: a.FinishedAt,
+				DurationMs: a.Duration().Milliseconds(),
+			}
+		}
+		writeJSON(w, http.StatusOK, resp)
+	}
+}
+
+// RequeueDeadJobsHandler requeues the dead jobs selected by the body.
+func RequeueDeadJobsHandler(adminSvc *jobs.AdminService) http.HandlerFunc {
+	return deadJobsAction(adminSvc.Requeue)
+}
+
+// DiscardDeadJobsHandler deletes the dead jobs selected by the body.
+func DiscardDeadJobsHandler(adminSvc *jobs.AdminService) http.HandlerFunc {
+	return deadJobsAction(adminSvc.Discard)
+}
+
+// RequeueJobHandler requeues a single dead job.
+func RequeueJobHandler(adminSvc *jobs.AdminService) http.HandlerFunc {
+	return deadJobAction(adminSvc.Requeue)
+}
+
+// DiscardJobHandler deletes a single dead job.
+func DiscardJobHandler(adminSvc *jobs.AdminService) http.HandlerFunc {
+	return deadJobAction(adminSvc.Discard)
+}
+
+type deadJobsFunc func(ctx context.Context, filter jobs.DeadJobFilter) ([]uuid.UUID, error)
+
+func deadJobsAction(action deadJobsFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req DeadJobsActionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request payload")
+			return
+		}
+
+		filter := jobs.DeadJobFilter{Type: req.Type, Error: req.Error}
+		for _, raw := range req.IDs {
+			id, err := uuid.Parse(raw)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, "invalid ids")
+				return
+			}
+			filter.IDs = append(filter.IDs, id)
+		}
+
+		ids, err := action(r.Context(), filter)
+		if err != nil {
+			writeJobsError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, deadJobsActionResponse{Count: len(ids), IDs: ids})
+	}
+}
+
+func deadJobAction(action deadJobsFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		jobID, err := uuid.Parse(chi.URLParam(r, "jobID"))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid jobId")
+			return
+		}
+
+		ids, err := action(r.Context(), jobs.DeadJobFilter{IDs: []uuid.UUID{jobID}})
+		if err != nil {
+			writeJobsError(w, err)
+			return
+		}
+		// Only failed jobs can be requeued or discarded
+		if len(ids) == 0 {
+			writeError(w, http.StatusNotFound, "not found")
+			return
+		}
+		writeJSON(w, http.StatusOK, deadJobsActionResponse{Count: len(ids), IDs: ids})
+	}
+}
+
+func writeJobsError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, jobs.ErrBadInput):
+		writeError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, jobs.ErrJobNotFound):
+		writeError(w, http.StatusNotFound, "not found")
+	default:
+		writeError(w, http.StatusInternalServerError, "internal server error")
+	}
+}
@@ -2,7 +2,10 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+
+	"resume-tailor/internal/httpapi/middleware"
 )
 
 func writeJSON(w http.ResponseWriter, status int, data any) {
@@ -28,3 +31,20 @@ func writeError(w http.ResponseWriter, status int, msg string) {
 
 	writeJSON(w, status, payload)
 }
+
+// decodeJSON reads the request body into dst, answering 413 for bodies over
+// middleware.MaxBodyBytes and 400 for anything else it cannot decode. It
+// reports whether dst was filled.
+func decodeJSON(w http.ResponseWriter, r *http.Request, dst any) bool {
+	err := json.NewDecoder(http.MaxBytesReader(w, r.Body, middleware.MaxBodyBytes)).Decode(dst)
+	if err == nil {
+		return true
+	}
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		writeError(w, http.StatusRequestEntityTooLarge, "request body too large")
+		return false
+	}
+	writeError(w, http.StatusBadRequest, "invalid request payload")
+	return false
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"resume-tailor/internal/httpapi/middleware"
+)
+
+func TestDecodeJSON(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantOK     bool
+		wantStatus int
+	}{
+		{name: "valid", body: `{"title": "CV"}`, wantOK: true},
+		{name: "malformed", body: `{"title": `, wantStatus: http.StatusBadRequest},
+		{name: "too large", body: `{"title": "` + strings.Repeat("x", middleware.MaxBodyBytes) + `"}`, wantStatus: http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/v1/resumes", strings.NewReader(tt.body))
+			var dst CreateResumeRequest
+			ok := decodeJSON(rec, req, &dst)
+			if ok != tt.wantOK {
+				t.Fatalf("decodeJSON = %v, want %v", ok, tt.wantOK)
+			}
+			if ok {
+				if dst.Title != "CV" {
+					t.Errorf("title %q, want CV", dst.Title)
+				}
+				return
+			}
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"encoding/json"
 	"net/http"
 	"strings"
 
@@ -29,8 +28,7 @@ func CreateResumeHandler(resumesSvc *resumes.Service) http.HandlerFunc {
 
 		// 2. Decode JSON body
 		var req CreateResumeRequest
-		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			writeError(w, http.StatusBadRequest, "invalid request payload")
+		if !decodeJSON(w, r, &req) {
 			return
 		}
 
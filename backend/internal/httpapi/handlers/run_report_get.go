@@ -1,8 +1,10 @@
 package handlers
 
 import (
+	"encoding/json"
 	"errors"
 	"net/http"
+	"time"
 
 	"resume-tailor/internal/httpapi/middleware"
 	"resume-tailor/internal/runreports"
@@ -12,6 +14,35 @@ import (
 	"github.com/google/uuid"
 )
 
+// runReportResponse is a stored report as the API returns it. ATSReport and
+// ChangePlan are passed through in the shape named by SchemaVersion; the
+// provenance fields are set for LLM reports only.
+type runReportResponse struct {
+	RunID         uuid.UUID       `json:"run_id"`
+	SchemaVersion int             `json:"schema_version"`
+	Mode          runreports.Mode `json:"mode"`
+	ATSReport     json.RawMessage `json:"ats_report"`
+	ChangePlan    json.RawMessage `json:"change_plan"`
+	PromptVersion *string         `json:"prompt_version,omitempty"`
+	Model         *string         `json:"model,omitempty"`
+	Provider      *string         `json:"provider,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+func toRunReportResponse(r runreports.RunReport) runReportResponse {
+	return runReportResponse{
+		RunID:         r.RunID,
+		SchemaVersion: r.SchemaVersion,
+		Mode:          r.Mode,
+		ATSReport:     r.ATSReport,
+		ChangePlan:    r.ChangePlan,
+		PromptVersion: r.PromptVersion,
+		Model:         r.Model,
+		Provider:      r.Provider,
+		CreatedAt:     r.CreatedAt,
+	}
+}
+
 func GetRunReportHandler(runsSvc *runs.Service, reportsSvc *runreports.Service) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		userID, ok := middleware.UserIDFromContext(r.Context())
@@ -49,7 +80,7 @@ func GetRunReportHandler(runsSvc *runs.Service, reportsSvc *runreports.Service)
 			return
 		}
 
-		writeJSON(w, http.StatusOK, report)
+		writeJSON(w, http.StatusOK, toRunReportResponse(report))
 	}
 }
 
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"resume-tailor/internal/runreports"
+
+	"github.com/google/uuid"
+)
+
+func TestRunReportResponseJSON(t *testing.T) {
+	str := func(s string) *string { return &s }
+	tests := []struct {
+		name   string
+		report runreports.RunReport
+		want   []string
+	}{
+		{
+			name: "llm",
+			report: runreports.RunReport{
+				RunID:         uuid.New(),
+				ATSReport:     json.RawMessage(`{"score":0.7}`),
+				ChangePlan:    json.RawMessage(`{"items":[]}`),
+				Mode:          runreports.ModeLLM,
+				SchemaVersion: 2,
+				CreatedAt:     time.Now(),
+				PromptVersion: str("v2"),
+				Model:         str("gpt-4o-mini"),
+				Provider:      str("openai"),
+				CacheKey:      str("abc"),
+				CacheHit:      true,
+			},
+			want: []string{"ats_report", "change_plan", "created_at", "mode", "model", "prompt_version", "provider", "run_id", "schema_version"},
+		},
+		{
+			name: "deterministic",
+			report: runreports.RunReport{
+				RunID:         uuid.New(),
+				ATSReport:     json.RawMessage(`{"score":0.4}`),
+				ChangePlan:    json.RawMessage(`{"changes":[]}`),
+				Mode:          runreports.ModeDeterministic,
+				SchemaVersion: 1,
+				CreatedAt:     time.Now(),
+			},
+			want: []string{"ats_report", "change_plan", "created_at", "mode", "run_id", "schema_version"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := json.Marshal(toRunReportResponse(tt.report))
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			var fields map[string]json.RawMessage
+			if err := json.Unmarshal(body, &fields); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			keys := make([]string, 0, len(fields))
+			for k := range fields {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			if !reflect.DeepEqual(keys, tt.want) {
+				t.Errorf("fields %v, want %v", keys, tt.want)
+			}
+			if string(fields["ats_report"]) != string(tt.report.ATSReport) {
+				t.Errorf("ats_report %s, want %s", fields["ats_report"], tt.report.ATSReport)
+			}
+			if string(fields["mode"]) != `"`+string(tt.report.Mode)+`"` {
+				t.Errorf("mode %s, want %q", fields["mode"], tt.report.Mode)
+			}
+		})
+	}
+}
@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"encoding/json"
 	"errors"
 	"net/http"
 
@@ -15,6 +14,8 @@ import (
 type CreateRunRequest struct {
 	ResumeID string `json:"resumeId"`
 	JobText  string `json:"jobText"`
+	// BypassCache skips cached LLM replies for this run
+	BypassCache bool `json:"bypassCache"`
 }
 
 type CreateRunResponse struct {
@@ -30,8 +31,7 @@ func CreateRunHandler(runsSvc *runs.Service, resumesSvc *resumes.Service) http.H
 		}
 
 		var req CreateRunRequest
-		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			writeError(w, http.StatusBadRequest, "invalid request payload")
+		if !decodeJSON(w, r, &req) {
 			return
 		}
 
@@ -52,7 +52,7 @@ func CreateRunHandler(runsSvc *runs.Service, resumesSvc *resumes.Service) http.H
 			return
 		}
 
-		run, err := runsSvc.CreateRun(r.Context(), userID, resumeID, req.JobText)
+		run, err := runsSvc.CreateRun(r.Context(), userID, resumeID, req.JobText, req.BypassCache)
 		if err != nil {
 			if errors.Is(err, runs.ErrBadInput) {
 				// Return the detailed validation message (ex: "bad input: job_text")
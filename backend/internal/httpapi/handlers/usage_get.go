@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"resume-tailor/internal/httpapi/middleware"
+	"resume-tailor/internal/usage"
+)
+
+type usageResponse struct {
+	PeriodStart  time.Time `json:"periodStart"`
+	PeriodEnd    time.Time `json:"periodEnd"`
+	Calls        int       `json:"calls"`
+	InputTokens  int64     `json:"inputTokens"`
+	OutputTokens int64     `json:"outputTokens"`
+	CostUSD      float64   `json:"costUsd"`
+	// BudgetUSD is omitted when there is no per-user budget
+	BudgetUSD *float64 `json:"budgetUsd,omitempty"`
+}
+
+// GetUsageHandler returns the current user's LLM spend for their billing
+// period.
+func GetUsageHandler(usageSvc *usage.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := middleware.UserIDFromContext(r.Context())
+		if !ok {
+			writeError(w, http.StatusUnauthorized, "unauthorized")
+			return
+		}
+
+		spend, err := usageSvc.UserSpend(r.Context(), userID, time.Now())
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "internal server error")
+			return
+		}
+
+		resp := usageResponse{
+			PeriodStart:  spend.Period.Start,
+			PeriodEnd:    spend.Period.End,
+			Calls:        spend.Calls,
+			InputTokens:  spend.InputTokens,
+			OutputTokens: spend.OutputTokens,
+			CostUSD:      spend.CostUSD,
+		}
+		if b := usageSvc.Budgets().UserMonthlyUSD; b > 0 {
+			resp.BudgetUSD = &b
+		}
+
+		writeJSON(w, http.StatusOK, resp)
+	}
+}
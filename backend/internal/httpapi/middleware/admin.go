@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+)
+
+// AdminTokenHeader carries the operator token for /v1/admin routes.
+const AdminTokenHeader = "X-Admin-Token"
+
+// AdminRequired rejects requests whose X-Admin-Token header does not match
+// token with a 401 Unauthorized JSON response.
+func AdminRequired(token string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got := r.Header.Get(AdminTokenHeader)
+			if token == "" || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+				writeUnauthorized(w)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"resume-tailor/internal/idempotency"
+)
+
+const (
+	// IdempotencyKeyHeader names the client-chosen key for a request.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on responses replayed for a key.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+// MaxBodyBytes bounds the request bodies handlers read. Idempotent reads
+// the body with the same limit before the handler does, so an oversized
+// request gets 413 whether or not it carries a key.
+const MaxBodyBytes = 1 << 20
+
+// idempotencyWriteTimeout bounds storing or releasing a key after the
+// handler ran, even if the client went away.
+const idempotencyWriteTimeout = 5 * time.Second
+
+// Idempotent makes a POST handler safe to retry. A request carrying an
+// Idempotency-Key header runs once per user and key; repeating it with
+// the same method, path and body replays the stored response, while
+// reusing the key for a different request gets 422 and racing the first
+// request gets 409. Responses with a 5xx status are not stored, so the
+// client can retry them. Requests without the header pass through, and
+// bodies over MaxBodyBytes get 413. It must run after AuthRequired.
+func Idempotent(repo *idempotency.Repo) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := strings.TrimSpace(r.Header.Get(IdempotencyKeyHeader))
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > idempotency.MaxKeyLength {
+				writeJSONError(w, http.StatusBadRequest, "invalid Idempotency-Key")
+				return
+			}
+
+			userID, ok := UserIDFromContext(r.Context())
+			if !ok {
+				writeUnauthorized(w)
+				return
+			}
+
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, MaxBodyBytes))
+			if err != nil {
+				var tooLarge *http.MaxBytesError
+				if errors.As(err, &tooLarge) {
+					writeJSONError(w, http.StatusRequestEntityTooLarge, "request body too large")
+					return
+				}
+				writeJSONError(w, http.StatusBadRequest, "invalid request payload")
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			req := idempotency.Request{
+				UserID: userID,
+				Key:    key,
+				Method: r.Method,
+				Path:   r.URL.Path,
+				Hash:   hashBody(body),
+			}
+			lock, stored, err := repo.Begin(r.Context(), req)
+			switch {
+			case errors.Is(err, idempotency.ErrKeyReused):
+				writeJSONError(w, http.StatusUnprocessableEntity, "Idempotency-Key was already used for a different request")
+				return
+			case errors.Is(err, idempotency.ErrInProgress):
+				writeJSONError(w, http.StatusConflict, "a request with this Idempotency-Key is still in progress")
+				return
+			case err != nil:
+				slog.Error("failed to claim idempotency key", "error", err)
+				writeJSONError(w, http.StatusInternalServerError, "internal server error")
+				return
+			}
+
+			if stored != nil {
+				if stored.ContentType != "" {
+					w.Header().Set("Content-Type", stored.ContentType)
+				}
+				w.Header().Set(IdempotentReplayedHeader, "true")
+				w.WriteHeader(stored.StatusCode)
+				w.Write(stored.Body)
+				return
+			}
+
+			rec := &responseRecorder{ResponseWriter: w}
+			// Release the key if the handler panics, then let Recover answer
+			defer func() {
+				if p := recover(); p != nil {
+					releaseKey(r.Context(), repo, *lock)
+					panic(p)
+				}
+			}()
+			next.ServeHTTP(rec, r)
+
+			if rec.status == 0 {
+				rec.status = http.StatusOK
+			}
+
+			ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), idempotencyWriteTimeout)
+			defer cancel()
+			if rec.status >= http.StatusInternalServerError {
+				releaseKey(ctx, repo, *lock)
+				return
+			}
+			resp := idempotency.Response{
+				StatusCode:  rec.status,
+				ContentType: rec.Header().Get("Content-Type"),
+				Body:        rec.body.Bytes(),
+			}
+			if err := repo.Complete(ctx, *lock, resp); errors.Is(err, idempotency.ErrLockLost) {
+				slog.Warn("idempotency key was taken over before the response was stored", "key", key)
+			} else if err != nil {
+				slog.Error("failed to store idempotent response", "error", err)
+			}
+		})
+	}
+}
+
+func releaseKey(ctx context.Context, repo *idempotency.Repo, lock idempotency.Lock) {
+	err := repo.Release(context.WithoutCancel(ctx), lock)
+	if err != nil && !errors.Is(err, idempotency.ErrLockLost) {
+		slog.Error("failed to release idempotency key", "error", err)
+	}
+}
+
+func hashBody(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+// responseRecorder passes the response through while keeping a copy of
+// its status and body.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (rr *responseRecorder) WriteHeader(code int) {
+	if rr.status == 0 {
+		rr.status = code
+	}
+	rr.ResponseWriter.WriteHeader(code)
+}
+
+func (rr *responseRecorder) Write(b []byte) (int, error) {
+	if rr.status == 0 {
+		rr.status = http.StatusOK
+	}
+	rr.body.Write(b)
+	return rr.ResponseWriter.Write(b)
+}
+
+func writeJSONError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{
+		"error": msg,
+	})
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// TestIdempotentBodyTooLarge checks that oversized bodies are rejected
+// before the key is claimed, so the repo is never reached.
+func TestIdempotentBodyTooLarge(t *testing.T) {
+	called := false
+	handler := Idempotent(nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+
+	body := strings.NewReader(`{"jobText": "` + strings.Repeat("x", MaxBodyBytes) + `"}`)
+	req := httptest.NewRequest(http.MethodPost, "/v1/runs", body)
+	req.Header.Set(IdempotencyKeyHeader, "key-1")
+	req = req.WithContext(WithUserID(req.Context(), uuid.New()))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("status %d, want %d", rec.Code, http.StatusRequestEntityTooLarge)
+	}
+	if called {
+		t.Error("handler ran for an oversized body")
+	}
+}
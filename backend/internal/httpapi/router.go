@@ -6,14 +6,17 @@ import (
 	"resume-tailor/internal/auth"
 	"resume-tailor/internal/httpapi/handlers"
 	"resume-tailor/internal/httpapi/middleware"
+	"resume-tailor/internal/idempotency"
+	"resume-tailor/internal/jobs"
 	"resume-tailor/internal/resumes"
 	"resume-tailor/internal/runreports"
 	"resume-tailor/internal/runs"
+	"resume-tailor/internal/usage"
 
 	"github.com/go-chi/chi/v5"
 )
 
-func NewRouter(authSvc *auth.Service, runsSvc *runs.Service, resumesSvc *resumes.Service, reportsSvc *runreports.Service) http.Handler {
+func NewRouter(authSvc *auth.Service, runsSvc *runs.Service, resumesSvc *resumes.Service, reportsSvc *runreports.Service, usageSvc *usage.Service, idemRepo *idempotency.Repo, adminSvc *jobs.AdminService, adminToken string) http.Handler {
 	r := chi.NewRouter()
 
 	// Global middleware
@@ -41,12 +44,27 @@ func NewRouter(authSvc *auth.Service, runsSvc *runs.Service, resumesSvc *resumes
 			r.Get("/runs", handlers.ListRunsHandler(runsSvc))
 			r.Get("/resumes", handlers.ListResumesHandler(resumesSvc))
 			r.Get("/resumes/{resumeID}", handlers.GetResumeByIDHandler(resumesSvc))
+			r.Get("/usage", handlers.GetUsageHandler(usageSvc))
 
-			//POST request
-			r.Post("/runs", handlers.CreateRunHandler(runsSvc, resumesSvc))
-			r.Post("/resumes", handlers.CreateResumeHandler(resumesSvc))
+			//POST request, safe to retry with an Idempotency-Key
+			r.With(middleware.Idempotent(idemRepo)).Post("/runs", handlers.CreateRunHandler(runsSvc, resumesSvc))
+			r.With(middleware.Idempotent(idemRepo)).Post("/resumes", handlers.CreateResumeHandler(resumesSvc))
 		})
 
+		// Operator routes, only mounted when ADMIN_TOKEN is set
+		if adminToken != "" {
+			r.Route("/admin", func(r chi.Router) {
+				r.Use(middleware.AdminRequired(adminToken))
+
+				r.Get("/jobs/dead", handlers.ListDeadJobsHandler(adminSvc))
+				r.Post("/jobs/dead/requeue", handlers.RequeueDeadJobsHandler(adminSvc))
+				r.Post("/jobs/dead/discard", handlers.DiscardDeadJobsHandler(adminSvc))
+				r.Get("/jobs/{jobID}", handlers.GetJobHandler(adminSvc))
+				r.Post("/jobs/{jobID}/requeue", handlers.RequeueJobHandler(adminSvc))
+				r.Post("/jobs/{jobID}/discard", handlers.DiscardJobHandler(adminSvc))
+			})
+		}
+
 	})
 
 	// NotFound handler returns JSON 404
@@ -0,0 +1,192 @@
+// Package idempotency stores Idempotency-Key values per user together with
+// a hash of the request and the response it produced, so that a retried
+// request is answered with the original response instead of running again.
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// lockTimeout is how long a key stays locked by a request that never
+// completed (e.g. the server crashed) before another request may take it.
+const lockTimeout = time.Minute
+
+// MaxKeyLength bounds the Idempotency-Key header value.
+const MaxKeyLength = 255
+
+var (
+	// ErrInProgress means the first request with the key is still running.
+	ErrInProgress = errors.New("idempotency: request with this key is in progress")
+	// ErrKeyReused means the key was used before with a different request.
+	ErrKeyReused = errors.New("idempotency: key reused with a different request")
+	// ErrLockLost means the key was taken over by another request after
+	// lockTimeout.
+	ErrLockLost = errors.New("idempotency: key lock lost")
+)
+
+// Request identifies a request made under an idempotency key.
+type Request struct {
+	UserID uuid.UUID
+	Key    string
+	Method string
+	Path   string
+	Hash   string
+}
+
+// Lock is a key claimed by Begin. LockedAt tells it apart from a later
+// takeover of the same key.
+type Lock struct {
+	UserID   uuid.UUID
+	Key      string
+	LockedAt time.Time
+}
+
+// Response is a stored response to replay.
+type Response struct {
+	StatusCode  int
+	ContentType string
+	Body        []byte
+}
+
+// Repo keeps keys for ttl after they are first used.
+type Repo struct {
+	db  *pgxpool.Pool
+	ttl time.Duration
+}
+
+func NewRepo(db *pgxpool.Pool, ttl time.Duration) *Repo {
+	return &Repo{db: db, ttl: ttl}
+}
+
+// Begin claims req's key. It returns a Lock when the caller should run the
+// request and then Complete or Release it, and the stored response when
+// the same request already completed. A request with a different method,
+// path or hash gets ErrKeyReused; one racing the first request gets
+// ErrInProgress.
+func (r *Repo) Begin(ctx context.Context, req Request) (*Lock, *Response, error) {
+	if req.UserID == uuid.Nil {
+		return nil, nil, fmt.Errorf("bad input: user_id")
+	}
+	if req.Key == "" || len(req.Key) > MaxKeyLength {
+		return nil, nil, fmt.Errorf("bad input: key")
+	}
+
+	// Insert the key, or take over one that expired or whose request died
+	// without completing
+	const claimQ = `
+INSERT INTO idempotency_keys (user_id, key, method, path, request_hash, locked_at, expires_at)
+VALUES ($1, $2, $3, $4, $5, now(), now() + $6::interval)
+ON CONFLICT (user_id, key) DO UPDATE
+SET method = EXCLUDED.method,
+    path = EXCLUDED.path,
+    request_hash = EXCLUDED.request_hash,
+    status_code = NULL,
+    content_type = NULL,
+    response_body = NULL,
+    locked_at = now(),
+    completed_at = NULL,
+    created_at = now(),
+    expires_at = EXCLUDED.expires_at
+WHERE idempotency_keys.expires_at <= now()
+   OR (idempotency_keys.completed_at IS NULL AND idempotency_keys.locked_at < now() - $7::interval)
+RETURNING locked_at`
+
+	lock := &Lock{UserID: req.UserID, Key: req.Key}
+	err := r.db.QueryRow(ctx, claimQ, req.UserID, req.Key, req.Method, req.Path, req.Hash, r.ttl, lockTimeout).Scan(&lock.LockedAt)
+	if err == nil {
+		return lock, nil, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil, err
+	}
+
+	const getQ = `
+SELECT method, path, request_hash, status_code, content_type, response_body
+FROM idempotency_keys
+WHERE user_id = $1 AND key = $2`
+
+	var (
+		method, path, hash string
+		status             *int
+		contentType        *string
+		body               []byte
+	)
+	err = r.db.QueryRow(ctx, getQ, req.UserID, req.Key).Scan(&method, &path, &hash, &status, &contentType, &body)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Released between the two statements; the client can retry
+		return nil, nil, ErrInProgress
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if method != req.Method || path != req.Path || hash != req.Hash {
+		return nil, nil, ErrKeyReused
+	}
+	if status == nil {
+		return nil, nil, ErrInProgress
+	}
+
+	resp := &Response{StatusCode: *status, Body: body}
+	if contentType != nil {
+		resp.ContentType = *contentType
+	}
+	return nil, resp, nil
+}
+
+// Complete stores the response for a key claimed with Begin. It returns
+// ErrLockLost when another request has taken the key over since.
+func (r *Repo) Complete(ctx context.Context, lock Lock, resp Response) error {
+	const q = `
+UPDATE idempotency_keys
+SET status_code = $4,
+    content_type = $5,
+    response_body = $6,
+    completed_at = now()
+WHERE user_id = $1 AND key = $2 AND locked_at = $3 AND completed_at IS NULL`
+
+	cmdTag, err := r.db.Exec(ctx, q, lock.UserID, lock.Key, lock.LockedAt, resp.StatusCode, resp.ContentType, resp.Body)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return ErrLockLost
+	}
+	return nil
+}
+
+// Release forgets a key claimed with Begin whose request failed, so the
+// client may retry it. Like Complete it returns ErrLockLost when another
+// request holds the key now.
+func (r *Repo) Release(ctx context.Context, lock Lock) error {
+	const q = `
+DELETE FROM idempotency_keys
+WHERE user_id = $1 AND key = $2 AND locked_at = $3 AND completed_at IS NULL`
+
+	cmdTag, err := r.db.Exec(ctx, q, lock.UserID, lock.Key, lock.LockedAt)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return ErrLockLost
+	}
+	return nil
+}
+
+// DeleteExpired removes expired keys and returns how many were deleted.
+func (r *Repo) DeleteExpired(ctx context.Context) (int64, error) {
+	const q = `DELETE FROM idempotency_keys WHERE expires_at <= now()`
+
+	cmdTag, err := r.db.Exec(ctx, q)
+	if err != nil {
+		return 0, err
+	}
+	return cmdTag.RowsAffected(), nil
+}
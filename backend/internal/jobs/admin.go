@@ -0,0 +1,83 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+)
+
+// AdminService is the operator's view of the dead-letter queue: jobs that
+// failed for good, with their attempt history, and the means to requeue or
+// discard them.
+type AdminService struct {
+	repo *Repo
+}
+
+func NewAdminService(repo *Repo) *AdminService {
+	return &AdminService{repo: repo}
+}
+
+// JobDetail is a job with its full attempt history.
+type JobDetail struct {
+	Job      Job
+	Attempts []Attempt
+}
+
+func (s *AdminService) ListDeadJobs(ctx context.Context, filter DeadJobFilter, limit, offset int) ([]Job, error) {
+	if limit <= 0 {
+		return nil, fmt.Errorf("%w: limit", ErrBadInput)
+	}
+	if offset < 0 {
+		return nil, fmt.Errorf("%w: offset", ErrBadInput)
+	}
+	return s.repo.ListDeadJobs(ctx, filter, limit, offset)
+}
+
+func (s *AdminService) GetJob(ctx context.Context, jobID uuid.UUID) (JobDetail, error) {
+	if jobID == uuid.Nil {
+		return JobDetail{}, fmt.Errorf("%w: job_id", ErrBadInput)
+	}
+
+	job, err := s.repo.GetJob(ctx, jobID)
+	if err != nil {
+		return JobDetail{}, err
+	}
+	attempts, err := s.repo.ListAttempts(ctx, jobID)
+	if err != nil {
+		return JobDetail{}, err
+	}
+	return JobDetail{Job: job, Attempts: attempts}, nil
+}
+
+// Requeue puts the dead jobs matching filter back in the queue with fresh
+// attempts and resets their runs to queued. An empty filter is rejected
+// so a bulk requeue always names what it targets.
+func (s *AdminService) Requeue(ctx context.Context, filter DeadJobFilter) ([]uuid.UUID, error) {
+	if filter.empty() {
+		return nil, fmt.Errorf("%w: filter", ErrBadInput)
+	}
+
+	ids, err := s.repo.RequeueDeadJobs(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("requeued dead jobs", "count", len(ids), "type", filter.Type, "error", filter.Error, "ids", len(filter.IDs))
+	return ids, nil
+}
+
+// Discard deletes the dead jobs matching filter. Like Requeue it rejects
+// an empty filter.
+func (s *AdminService) Discard(ctx context.Context, filter DeadJobFilter) ([]uuid.UUID, error) {
+	if filter.empty() {
+		return nil, fmt.Errorf("%w: filter", ErrBadInput)
+	}
+
+	ids, err := s.repo.DiscardDeadJobs(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("discarded dead jobs", "count", len(ids), "type", filter.Type, "error", filter.Error, "ids", len(filter.IDs))
+	return ids, nil
+}
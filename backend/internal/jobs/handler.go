@@ -0,0 +1,68 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+)
+
+// Handler runs one attempt of a job. A returned error fails the attempt;
+// the worker retries it with backoff unless the error is Permanent or the
+// job is out of attempts.
+type Handler interface {
+	Handle(ctx context.Context, job Job) error
+}
+
+// HandlerFunc adapts a function to Handler.
+type HandlerFunc func(ctx context.Context, job Job) error
+
+func (f HandlerFunc) Handle(ctx context.Context, job Job) error {
+	return f(ctx, job)
+}
+
+// HandlerOptions tune how a worker runs one job type. Concurrency is the
+// number of slots claiming the type (0 uses the worker's concurrency);
+// a positive MaxAttempts overrides the limit jobs were enqueued with.
+type HandlerOptions struct {
+	Concurrency int
+	MaxAttempts int
+}
+
+type registration struct {
+	jobType string
+	handler Handler
+	opts    HandlerOptions
+}
+
+// Registry maps job types to the handlers a worker runs them with. Jobs of
+// types that are not registered stay queued.
+type Registry struct {
+	handlers []registration
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register adds the handler for jobType. Like http.Handle it panics on
+// programmer errors: an empty type, a nil handler or a duplicate type.
+func (r *Registry) Register(jobType string, h Handler, opts HandlerOptions) *Registry {
+	if jobType == "" || h == nil {
+		panic("jobs: Register needs a job type and a handler")
+	}
+	for _, reg := range r.handlers {
+		if reg.jobType == jobType {
+			panic(fmt.Sprintf("jobs: handler for %q already registered", jobType))
+		}
+	}
+	r.handlers = append(r.handlers, registration{jobType: jobType, handler: h, opts: opts})
+	return r
+}
+
+// Types lists the registered job types in registration order.
+func (r *Registry) Types() []string {
+	types := make([]string, len(r.handlers))
+	for i, reg := range r.handlers {
+		types[i] = reg.jobType
+	}
+	return types
+}
@@ -0,0 +1,89 @@
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// NotifyChannel is the Postgres channel notified when a job is queued.
+const NotifyChannel = "jobs_queued"
+
+const listenRetryDelay = 5 * time.Second
+
+// Listener holds a dedicated connection that LISTENs on NotifyChannel and
+// wakes the worker on every notification. When the connection drops it
+// reconnects after a delay; meanwhile Connected reports false so the
+// worker falls back to polling.
+type Listener struct {
+	db        *pgxpool.Pool
+	connected atomic.Bool
+}
+
+func NewListener(db *pgxpool.Pool) *Listener {
+	return &Listener{db: db}
+}
+
+// Connected reports whether the listener is currently receiving notifications.
+func (l *Listener) Connected() bool {
+	return l.connected.Load()
+}
+
+// Run listens until ctx is cancelled, sending on wake for every
+// notification and whenever the connection is lost or restored, since
+// notifications may have been missed in between. wake should be buffered;
+// sends never block.
+func (l *Listener) Run(ctx context.Context, wake chan<- struct{}) {
+	for {
+		err := l.listen(ctx, wake)
+		if ctx.Err() != nil {
+			return
+		}
+		if l.connected.Swap(false) {
+			signal(wake)
+		}
+		slog.Warn("job listener disconnected, polling until it reconnects", "error", err, "retry_in", listenRetryDelay)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(listenRetryDelay):
+		}
+	}
+}
+
+func (l *Listener) listen(ctx context.Context, wake chan<- struct{}) error {
+	pooled, err := l.db.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	// Take the connection out of the pool so a LISTEN registration is never
+	// handed to another caller; it is closed when listening stops
+	conn := pooled.Hijack()
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+NotifyChannel); err != nil {
+		return err
+	}
+	l.connected.Store(true)
+	signal(wake)
+	slog.Info("job listener connected", "channel", NotifyChannel)
+
+	for {
+		if _, err := conn.WaitForNotification(ctx); err != nil {
+			return err
+		}
+		signal(wake)
+	}
+}
+
+// signal sends on ch without blocking; a pending wakeup is enough.
+func signal(ch chan<- struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,277 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"resume-tailor/internal/ai"
+	"resume-tailor/internal/resumes"
+	"resume-tailor/internal/runreports"
+	"resume-tailor/internal/scoring"
+	"resume-tailor/internal/usage"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Report modes, selected with REPORT_MODE.
+const (
+	ReportModeAuto          = "auto"
+	ReportModeLLM           = "llm"
+	ReportModeDeterministic = "deterministic"
+)
+
+const (
+	runStatusCreated    = "created"
+	runStatusQueued     = "queued"
+	runStatusProcessing = "processing"
+	runStatusFailed     = "failed"
+	runStatusCompleted  = "completed"
+)
+
+// RunsRepo is an interface to avoid import cycle with runs package
+type RunsRepo interface {
+	GetRunByID(ctx context.Context, runID uuid.UUID) (RunData, error)
+}
+
+// ReportGenerator produces an ATS report and change plan for a run. It is
+// implemented by *ai.Client for any configured LLM provider.
+type ReportGenerator interface {
+	GenerateRunReport(ctx context.Context, resumeText, jobText string, signals *scoring.Signals) (ai.Report, error)
+	Info() ai.GeneratorInfo
+}
+
+// RunData represents the run data needed by the worker
+type RunData struct {
+	ID           uuid.UUID
+	UserID       uuid.UUID
+	ResumeID     uuid.UUID
+	JobText      string
+	Status       string
+	ErrorMessage *string
+	BypassCache  bool
+}
+
+// RunProcessor is the Handler for process_run jobs: it scores the resume
+// against the posting, generates the report and stores it with the run's
+// artifacts.
+type RunProcessor struct {
+	jobsRepo    *Repo
+	db          *pgxpool.Pool
+	reportsSvc  *runreports.Service
+	runsRepo    RunsRepo
+	resumesRepo *resumes.Repo
+	generator   ReportGenerator
+	scorer      *scoring.Scorer
+	reportMode  string
+
+	// schemaVersion is the report schema for deterministic reports; LLM
+	// reports use the schema of the generator's prompt.
+	schemaVersion int
+
+	// usageSvc records LLM calls and enforces budgets; nil disables both
+	usageSvc *usage.Service
+}
+
+func NewRunProcessor(jobsRepo *Repo, db *pgxpool.Pool, reportsSvc *runreports.Service, runsRepo RunsRepo, resumesRepo *resumes.Repo, generator ReportGenerator, scorer *scoring.Scorer, reportMode string, schemaVersion int, usageSvc *usage.Service) *RunProcessor {
+	return &RunProcessor{
+		jobsRepo:    jobsRepo,
+		db:          db,
+		reportsSvc:  reportsSvc,
+		runsRepo:    runsRepo,
+		resumesRepo: resumesRepo,
+		generator:   generator,
+		scorer:      scorer,
+		reportMode:  reportMode,
+
+		schemaVersion: schemaVersion,
+		usageSvc:      usageSvc,
+	}
+}
+
+// Handle processes a process_run job: it moves the run through processing
+// to completed, generating its report on the way. Failed attempts leave
+// the run to the worker, which requeues or fails it along with the job.
+func (p *RunProcessor) Handle(ctx context.Context, job Job) error {
+	var payload ProcessRunPayload
+	if err := job.Decode(&payload); err != nil {
+		return err
+	}
+	runID := payload.RunID
+
+	// Update run status to processing
+	if err := p.jobsRepo.UpdateRunStatus(ctx, runID, runStatusProcessing, nil); err != nil {
+		slog.Error("failed to update run status to processing", "error", err, "run_id", runID)
+		return fmt.Errorf("failed to update run status: %w", err)
+	}
+
+	// Process the run (MVP stub)
+	lastAttempt := job.Attempts >= job.MaxAttempts
+	if err := p.processRun(ctx, runID, lastAttempt); err != nil {
+		slog.Error("failed to process run", "error", err, "run_id", runID)
+		return err
+	}
+
+	// Success: update run status to completed
+	if err := p.jobsRepo.UpdateRunStatus(ctx, runID, runStatusCompleted, nil); err != nil {
+		slog.Error("failed to update run status to completed", "error", err, "run_id", runID)
+		return fmt.Errorf("failed to update run status: %w", err)
+	}
+	return nil
+}
+
+func (p *RunProcessor) processRun(ctx context.Context, runID uuid.UUID, lastAttempt bool) error {
+	// Check if an LLM is available
+	if p.reportMode == ReportModeLLM && p.generator == nil {
+		return Permanent(fmt.Errorf("LLM provider not configured"))
+	}
+
+	// 1. Load the run
+	runData, err := p.runsRepo.GetRunByID(ctx, runID)
+	if err != nil {
+		return fmt.Errorf("failed to load run: %w", err)
+	}
+
+	// 2. Load the resume
+	resume, err := p.resumesRepo.GetResumeByID(ctx, runData.ResumeID)
+	if err != nil {
+		return fmt.Errorf("failed to load resume: %w", err)
+	}
+
+	resumeText := resume.ContentText
+	jobText := runData.JobText
+
+	// 3. Compute keyword and experience signals
+	var scoringSignals *scoring.Signals
+	signals, err := p.scorer.Compute(resumeText, jobText, time.Now())
+	if err != nil {
+		slog.Warn("scoring failed, continuing without signals", "error", err, "run_id", runID)
+	} else {
+		scoringSignals = &signals
+	}
+
+	// 4. Generate ATS report and change plan
+	genCtx := ctx
+	if runData.BypassCache {
+		genCtx = ai.WithCacheBypass(ctx)
+	}
+	generated, mode, err := p.generateReport(genCtx, runData, resumeText, scoringSignals, lastAttempt)
+	if err != nil {
+		return fmt.Errorf("failed to generate run report: %w", err)
+	}
+
+	// 5. Persist into run_reports
+	if p.reportsSvc != nil {
+		report := runreports.RunReport{
+			RunID:         runID,
+			ATSReport:     generated.ATSReport,
+			ChangePlan:    generated.ChangePlan,
+			SchemaVersion: generated.SchemaVersion,
+			Mode:          mode,
+		}
+		if mode == runreports.ModeLLM {
+			info := p.generator.Info()
+			report.PromptVersion = &info.PromptVersion
+			report.Model = &info.Model
+			report.Provider = &info.Provider
+			if generated.CacheKey != "" {
+				report.CacheKey = &generated.CacheKey
+				report.CacheHit = generated.CacheHit
+			}
+		}
+		if err := p.reportsSvc.UpsertRunReport(ctx, report); err != nil {
+			return fmt.Errorf("failed to upsert run report: %w", err)
+		}
+	}
+
+	// Placeholder JSON for artifacts (LaTeX/PDF generation not implemented yet)
+	resumeSpec := map[string]interface{}{
+		"version":   "1.0",
+		"sections":  []string{"placeholder section"},
+		"timestamp": time.Now().Unix(),
+	}
+
+	resumeSpecJSON, err := json.Marshal(resumeSpec)
+	if err != nil {
+		return fmt.Errorf("failed to marshal resume spec: %w", err)
+	}
+
+	// Insert into run_artifacts
+	const insertArtifactQ = `
+INSERT INTO run_artifacts (run_id, resume_spec, latex_path, pdf_path)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (run_id) DO UPDATE
+SET resume_spec = $2, latex_path = $3, pdf_path = $4, created_at = now()`
+
+	latexPath := fmt.Sprintf("/generated/%s/resume.tex", runID.String())
+	pdfPath := fmt.Sprintf("/generated/%s/resume.pdf", runID.String())
+
+	_, err = p.db.Exec(ctx, insertArtifactQ, runID, resumeSpecJSON, latexPath, pdfPath)
+	if err != nil {
+		return fmt.Errorf("failed to insert run artifact: %w", err)
+	}
+
+	return nil
+}
+
+// generateReport picks the LLM or the deterministic scorer according to the
+// report mode. In auto mode the deterministic report is used when no
+// generator is configured or the LLM call fails for good: with an error
+// that is not worth retrying, or on the job's last attempt. Transient
+// errors are returned earlier so the job is retried with backoff. A run
+// whose user or deployment is over its LLM budget fails in any mode that
+// calls the LLM, unless its report is answered from cache.
+func (p *RunProcessor) generateReport(ctx context.Context, run RunData, resumeText string, signals *scoring.Signals, lastAttempt bool) (ai.Report, runreports.Mode, error) {
+	if p.reportMode == ReportModeDeterministic || p.generator == nil {
+		report, err := ai.GenerateDeterministicReport(resumeText, signals, p.schemaVersion)
+		return report, runreports.ModeDeterministic, err
+	}
+
+	if p.usageSvc != nil {
+		ctx = ai.WithCallCheck(ctx, func(ctx context.Context) error {
+			return p.usageSvc.CheckBudget(ctx, run.UserID, time.Now())
+		})
+	}
+
+	report, err := p.generator.GenerateRunReport(ctx, resumeText, run.JobText, signals)
+	p.recordUsage(ctx, run, report, err)
+	if err == nil {
+		return report, runreports.ModeLLM, nil
+	}
+	if p.reportMode != ReportModeAuto || signals == nil || errors.Is(err, usage.ErrBudgetExceeded) {
+		return ai.Report{}, "", err
+	}
+	if retryable(err) && !lastAttempt {
+		return ai.Report{}, "", err
+	}
+
+	slog.Warn("LLM report failed, falling back to deterministic report", "error", err, "run_id", run.ID)
+	report, err = ai.GenerateDeterministicReport(resumeText, signals, p.schemaVersion)
+	return report, runreports.ModeDeterministic, err
+}
+
+// recordUsage stores the provider calls behind a report, or behind the
+// error when generation failed. Accounting failures are logged only.
+func (p *RunProcessor) recordUsage(ctx context.Context, run RunData, report ai.Report, err error) {
+	if p.usageSvc == nil {
+		return
+	}
+
+	calls := report.Calls
+	var reportErr *ai.ReportError
+	if errors.As(err, &reportErr) {
+		calls = reportErr.Calls
+	}
+	if len(calls) == 0 {
+		return
+	}
+
+	promptVersion := p.generator.Info().PromptVersion
+	if err := p.usageSvc.RecordCalls(ctx, run.ID, run.UserID, promptVersion, calls); err != nil {
+		slog.Error("failed to record LLM usage", "error", err, "run_id", run.ID)
+	}
+}
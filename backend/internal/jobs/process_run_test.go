@@ -0,0 +1,269 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"resume-tailor/internal/ai"
+	"resume-tailor/internal/auth"
+	"resume-tailor/internal/resumes"
+	"resume-tailor/internal/runreports"
+	"resume-tailor/internal/scoring"
+	"resume-tailor/internal/scoring/taxonomy"
+
+	"github.com/google/uuid"
+)
+
+const (
+	testResume = `Jane Doe
+Summary
+Backend engineer with 6 years of experience building Go services.
+Experience
+Senior Engineer, Acme (2019 - present)
+- Built Go microservices on PostgreSQL, cutting p99 latency by 40%.
+Skills
+Go, PostgreSQL, Docker`
+
+	testJob = `Senior Backend Engineer
+Requirements
+- 5+ years of experience with Go and PostgreSQL
+- Experience with Kubernetes
+Nice to have
+- Terraform`
+)
+
+// validReply is a minimal reply that passes ai.ReportSchemaV2.
+func validReply() ai.FakeResponse {
+	return ai.FakeJSON(ai.ReportResponseV2{
+		ATSReport: ai.ATSReportV2{
+			Score:         0.7,
+			SubScores:     ai.SubScores{KeywordCoverage: 0.6, ExperienceFit: 0.8, Formatting: 0.7, Impact: 0.6},
+			MatchedSkills: []ai.SkillEvidence{},
+			MissingSkills: []ai.MissingSkill{{Skill: "Kubernetes", Importance: "required"}},
+			Notes:         []string{"Strong Go background."},
+		},
+		ChangePlan: ai.ChangePlanV2{
+			Items: []ai.ChangeItem{{
+				ID:            "c1",
+				Priority:      "high",
+				Category:      "keywords",
+				TargetSection: "skills",
+				SuggestedText: "Add Kubernetes if you have used it.",
+				Rationale:     "The posting requires Kubernetes.",
+				Terms:         []string{"kubernetes"},
+			}},
+		},
+	})
+}
+
+func newTestScorer(t *testing.T) *scoring.Scorer {
+	t.Helper()
+	tax, err := taxonomy.Default()
+	if err != nil {
+		t.Fatalf("taxonomy: %v", err)
+	}
+	return scoring.NewScorer(tax)
+}
+
+func newTestGenerator(t *testing.T, fake *ai.FakeProvider) *ai.Client {
+	t.Helper()
+	prompts, err := ai.LoadPrompts()
+	if err != nil {
+		t.Fatalf("LoadPrompts: %v", err)
+	}
+	prompt, err := prompts.Latest(ai.PromptRunReport, ai.SchemaV2)
+	if err != nil {
+		t.Fatalf("Latest: %v", err)
+	}
+	return ai.NewClient(fake, prompt)
+}
+
+func testSignals(t *testing.T) *scoring.Signals {
+	t.Helper()
+	signals, err := newTestScorer(t).Compute(testResume, testJob, time.Now())
+	if err != nil {
+		t.Fatalf("Compute: %v", err)
+	}
+	return &signals
+}
+
+// TestGenerateReportScriptedReplies runs one report attempt per job attempt
+// against a malformed, an empty, a rate-limited and a valid reply. The
+// transient failures are returned for the worker to retry instead of
+// falling back to a deterministic report.
+func TestGenerateReportScriptedReplies(t *testing.T) {
+	ctx := context.Background()
+	fake := ai.NewFakeProvider("")
+	fake.Enqueue(ai.FakeMalformed(), ai.FakeEmpty(), ai.FakeError(http.StatusTooManyRequests, "rate limited"), validReply())
+	p := &RunProcessor{generator: newTestGenerator(t, fake), reportMode: ReportModeAuto, schemaVersion: ai.SchemaV2}
+	run := RunData{ID: uuid.New(), UserID: uuid.New(), JobText: testJob}
+	signals := testSignals(t)
+
+	// Malformed reply, then an empty reply to the repair request
+	if _, _, err := p.generateReport(ctx, run, testResume, signals, false); err == nil || !retryable(err) {
+		t.Fatalf("attempt 1: got %v, want a retryable error", err)
+	}
+	if n := len(fake.Requests()); n != 2 {
+		t.Errorf("attempt 1: %d requests, want 2 (reply and repair)", n)
+	}
+
+	// Rate limited
+	if _, _, err := p.generateReport(ctx, run, testResume, signals, false); err == nil || !retryable(err) {
+		t.Fatalf("attempt 2: got %v, want a retryable error", err)
+	}
+
+	// Valid reply
+	report, mode, err := p.generateReport(ctx, run, testResume, signals, false)
+	if err != nil {
+		t.Fatalf("attempt 3: %v", err)
+	}
+	if mode != runreports.ModeLLM || report.SchemaVersion != ai.SchemaV2 {
+		t.Errorf("attempt 3: mode %q schema v%d, want llm v2", mode, report.SchemaVersion)
+	}
+}
+
+func TestGenerateReportFallback(t *testing.T) {
+	tests := []struct {
+		name        string
+		mode        string
+		reply       ai.FakeResponse
+		lastAttempt bool
+		wantMode    runreports.Mode
+		wantErr     bool
+	}{
+		{name: "transient error is retried", mode: ReportModeAuto, reply: ai.FakeError(http.StatusTooManyRequests, "rate limited"), wantErr: true},
+		{name: "transient error on last attempt falls back", mode: ReportModeAuto, reply: ai.FakeError(http.StatusServiceUnavailable, "unavailable"), lastAttempt: true, wantMode: runreports.ModeDeterministic},
+		{name: "permanent error falls back", mode: ReportModeAuto, reply: ai.FakeError(http.StatusBadRequest, "bad request"), wantMode: runreports.ModeDeterministic},
+		{name: "llm mode does not fall back", mode: ReportModeLLM, reply: ai.FakeError(http.StatusBadRequest, "bad request"), lastAttempt: true, wantErr: true},
+		{name: "valid reply", mode: ReportModeAuto, reply: validReply(), wantMode: runreports.ModeLLM},
+	}
+
+	signals := testSignals(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := ai.NewFakeProvider("").Enqueue(tt.reply)
+			p := &RunProcessor{generator: newTestGenerator(t, fake), reportMode: tt.mode, schemaVersion: ai.SchemaV2}
+			run := RunData{ID: uuid.New(), UserID: uuid.New(), JobText: testJob}
+
+			report, mode, err := p.generateReport(context.Background(), run, testResume, signals, tt.lastAttempt)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("got mode %q, want an error", mode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("generateReport: %v", err)
+			}
+			if mode != tt.wantMode {
+				t.Errorf("mode %q, want %q", mode, tt.wantMode)
+			}
+			if !json.Valid(report.ATSReport) || !json.Valid(report.ChangePlan) {
+				t.Errorf("report is not valid JSON: %s / %s", report.ATSReport, report.ChangePlan)
+			}
+		})
+	}
+}
+
+// testRunsRepo serves runs created by the test.
+type testRunsRepo map[uuid.UUID]RunData
+
+func (r testRunsRepo) GetRunByID(ctx context.Context, runID uuid.UUID) (RunData, error) {
+	run, ok := r[runID]
+	if !ok {
+		return RunData{}, ErrRunNotFound
+	}
+	return run, nil
+}
+
+// TestRunProcessorHandle drives process_run jobs end to end with the fake
+// provider: transient failures leave the run processing for a retry, a
+// valid reply stores an LLM report, and a failure on the last attempt
+// stores a deterministic one.
+func TestRunProcessorHandle(t *testing.T) {
+	ctx := context.Background()
+	pool := testDB(t)
+
+	userID, err := auth.NewRepo(pool).CreateUser(ctx, "jobs-test-"+uuid.NewString()+"@example.com", "x", "Jobs Test")
+	if err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	t.Cleanup(func() {
+		if _, err := pool.Exec(context.Background(), "DELETE FROM users WHERE id = $1", userID); err != nil {
+			t.Errorf("cleanup: %v", err)
+		}
+	})
+	resumesRepo := resumes.NewRepo(pool)
+	resume, err := resumesRepo.CreateResume(ctx, userID, "Test resume", testResume)
+	if err != nil {
+		t.Fatalf("create resume: %v", err)
+	}
+
+	runsRepo := testRunsRepo{}
+	newRun := func() uuid.UUID {
+		var id uuid.UUID
+		err := pool.QueryRow(ctx, "INSERT INTO runs (user_id, resume_id, job_text, status) VALUES ($1, $2, $3, 'queued') RETURNING id", userID, resume.ID, testJob).Scan(&id)
+		if err != nil {
+			t.Fatalf("create run: %v", err)
+		}
+		runsRepo[id] = RunData{ID: id, UserID: userID, ResumeID: resume.ID, JobText: testJob}
+		return id
+	}
+	job := func(runID uuid.UUID, attempt int) Job {
+		payload, _ := json.Marshal(ProcessRunPayload{RunID: runID})
+		return Job{ID: uuid.New(), Type: JobTypeProcessRun, RunID: &runID, Payload: payload, Attempts: attempt, MaxAttempts: 3}
+	}
+	runStatus := func(runID uuid.UUID) string {
+		var status string
+		if err := pool.QueryRow(ctx, "SELECT status::text FROM runs WHERE id = $1", runID).Scan(&status); err != nil {
+			t.Fatalf("run status: %v", err)
+		}
+		return status
+	}
+
+	fake := ai.NewFakeProvider("")
+	fake.Enqueue(ai.FakeMalformed(), ai.FakeEmpty(), ai.FakeError(http.StatusTooManyRequests, "rate limited"), validReply(),
+		ai.FakeError(http.StatusTooManyRequests, "rate limited"))
+	reportsSvc := runreports.NewService(runreports.NewRepo(pool))
+	p := NewRunProcessor(NewRepo(pool), pool, reportsSvc, runsRepo, resumesRepo, newTestGenerator(t, fake), newTestScorer(t), ReportModeAuto, ai.SchemaV2, nil)
+
+	runID := newRun()
+	for attempt := 1; attempt <= 2; attempt++ {
+		err := p.Handle(ctx, job(runID, attempt))
+		if err == nil || !retryable(err) {
+			t.Fatalf("attempt %d: got %v, want a retryable error", attempt, err)
+		}
+		if status := runStatus(runID); status != runStatusProcessing {
+			t.Errorf("attempt %d: run is %s, want %s", attempt, status, runStatusProcessing)
+		}
+	}
+	if err := p.Handle(ctx, job(runID, 3)); err != nil {
+		t.Fatalf("attempt 3: %v", err)
+	}
+	if status := runStatus(runID); status != runStatusCompleted {
+		t.Errorf("run is %s, want %s", status, runStatusCompleted)
+	}
+	report, err := reportsSvc.GetRunReportByRunID(ctx, runID)
+	if err != nil {
+		t.Fatalf("get report: %v", err)
+	}
+	if report.Mode != runreports.ModeLLM {
+		t.Errorf("report mode %q, want llm", report.Mode)
+	}
+
+	// Rate limited on the last attempt: the deterministic report is stored
+	lastID := newRun()
+	if err := p.Handle(ctx, job(lastID, 3)); err != nil {
+		t.Fatalf("last attempt: %v", err)
+	}
+	report, err = reportsSvc.GetRunReportByRunID(ctx, lastID)
+	if err != nil {
+		t.Fatalf("get report: %v", err)
+	}
+	if report.Mode != runreports.ModeDeterministic {
+		t.Errorf("report mode %q, want deterministic", report.Mode)
+	}
+}
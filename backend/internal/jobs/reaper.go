@@ -0,0 +1,81 @@
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReapedJob is a job whose lock expired, with the status it was moved to.
+type ReapedJob struct {
+	ID       uuid.UUID
+	Type     string
+	RunID    *uuid.UUID
+	Status   string
+	LockedBy *string
+	Attempts int
+}
+
+// Reaper recovers jobs left running by workers that crashed or hung: a job
+// whose lease expired without a heartbeat is requeued, or failed when it
+// has used all its attempts, and its run is reset to match. Any number of
+// workers may run a Reaper; each stale job is reaped once.
+type Reaper struct {
+	jobsRepo *Repo
+	interval time.Duration
+}
+
+func NewReaper(jobsRepo *Repo, interval time.Duration) *Reaper {
+	return &Reaper{
+		jobsRepo: jobsRepo,
+		interval: interval,
+	}
+}
+
+// Run reaps stale jobs every interval until ctx is cancelled.
+func (r *Reaper) Run(ctx context.Context) error {
+	slog.Info("reaper started", "interval", r.interval)
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.ReapOnce(ctx); err != nil && ctx.Err() == nil {
+			slog.Error("failed to reap stale jobs", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// ReapOnce reaps the jobs that are stale right now.
+func (r *Reaper) ReapOnce(ctx context.Context) ([]ReapedJob, error) {
+	reaped, err := r.jobsRepo.ReapStaleJobs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, job := range reaped {
+		lockedBy := ""
+		if job.LockedBy != nil {
+			lockedBy = *job.LockedBy
+		}
+		slog.Warn("reaped stale job", "job_id", job.ID, "type", job.Type, "run_id", runIDAttr(job.RunID), "locked_by", lockedBy, "attempts", job.Attempts, "status", job.Status)
+	}
+
+	for _, job := range reaped {
+		if job.Status == JobStatusQueued {
+			if err := r.jobsRepo.Notify(ctx); err != nil {
+				slog.Warn("failed to notify workers", "error", err)
+			}
+			break
+		}
+	}
+	return reaped, nil
+}
@@ -2,8 +2,10 @@ package jobs
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/google/uuid"
@@ -29,44 +31,110 @@ func (r *Repo) EnqueueProcessRun(ctx context.Context, runID uuid.UUID) (uuid.UUI
 	if runID == uuid.Nil {
 		return uuid.Nil, fmt.Errorf("runID cannot be nil")
 	}
+	return r.Enqueue(ctx, JobTypeProcessRun, ProcessRunPayload{RunID: runID}, EnqueueOptions{RunID: &runID})
+}
+
+// Enqueue queues a job of jobType. The payload is stored as JSON and handed
+// to the type's Handler in Job.Payload.
+func (r *Repo) Enqueue(ctx context.Context, jobType string, payload any, opts EnqueueOptions) (uuid.UUID, error) {
+	if jobType == "" {
+		return uuid.Nil, fmt.Errorf("bad input: job type")
+	}
+	if opts.MaxAttempts < 0 {
+		return uuid.Nil, fmt.Errorf("bad input: max_attempts")
+	}
+	if payload == nil {
+		payload = struct{}{}
+	}
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to marshal job payload: %w", err)
+	}
+
+	maxAttempts := opts.MaxAttempts
+	if maxAttempts == 0 {
+		maxAttempts = DefaultMaxAttempts
+	}
+	var runAfter *time.Time
+	if !opts.RunAfter.IsZero() {
+		runAfter = &opts.RunAfter
+	}
 
 	const q = `
-INSERT INTO jobs (type, run_id, status)
-VALUES ($1, $2, $3)
+INSERT INTO jobs (type, run_id, payload, status, max_attempts, run_after)
+VALUES ($1, $2, $3, $4, $5, COALESCE($6, now()))
 RETURNING id`
 
 	var id uuid.UUID
-	err := r.db.QueryRow(ctx, q, JobTypeProcessRun, runID, JobStatusQueued).Scan(&id)
+	err = r.db.QueryRow(ctx, q, jobType, opts.RunID, payloadJSON, JobStatusQueued, maxAttempts, runAfter).Scan(&id)
 	if err != nil {
 		return uuid.Nil, err
 	}
 
+	// The job is already stored; a lost notification only delays it until
+	// the next poll
+	if err := r.Notify(ctx); err != nil {
+		slog.Warn("failed to notify workers", "error", err, "job_id", id)
+	}
+
 	return id, nil
 }
 
-func (r *Repo) ClaimNextProcessRun(ctx context.Context, workerID string) (Job, error) {
+// Notify wakes workers listening on NotifyChannel.
+func (r *Repo) Notify(ctx context.Context) error {
+	_, err := r.db.Exec(ctx, "SELECT pg_notify($1, '')", NotifyChannel)
+	return err
+}
+
+// NextRunAfter returns when the earliest queued job of jobType becomes
+// due, or false when none is queued.
+func (r *Repo) NextRunAfter(ctx context.Context, jobType string) (time.Time, bool, error) {
 	const q = `
-SELECT id, type, run_id, status, attempts, max_attempts, locked_by, locked_at, last_error, created_at, updated_at
+SELECT min(run_after)
 FROM jobs
-WHERE type = $1 AND status = $2
-ORDER BY created_at ASC
-FOR UPDATE SKIP LOCKED
-LIMIT 1`
+WHERE type = $1 AND status = $2`
 
-	var job Job
-	err := r.db.QueryRow(ctx, q, JobTypeProcessRun, JobStatusQueued).Scan(
-		&job.ID,
-		&job.Type,
-		&job.RunID,
-		&job.Status,
-		&job.Attempts,
-		&job.MaxAttempts,
-		&job.LockedBy,
-		&job.LockedAt,
-		&job.LastError,
-		&job.CreatedAt,
-		&job.UpdatedAt,
-	)
+	var next *time.Time
+	if err := r.db.QueryRow(ctx, q, jobType, JobStatusQueued).Scan(&next); err != nil {
+		return time.Time{}, false, err
+	}
+	if next == nil {
+		return time.Time{}, false, nil
+	}
+	return *next, true, nil
+}
+
+// Claim locks the oldest queued job of jobType that is due (run_after has
+// passed) and marks it running in one statement, leased to workerID for
+// lease. The row lock taken by the subquery is held until the UPDATE
+// commits, so concurrent workers skip the row instead of claiming it twice.
+// A positive maxAttempts replaces the limit the job was enqueued with.
+func (r *Repo) Claim(ctx context.Context, jobType, workerID string, lease time.Duration, maxAttempts int) (Job, error) {
+	if lease <= 0 {
+		return Job{}, fmt.Errorf("bad input: lease")
+	}
+
+	const q = `
+UPDATE jobs
+SET status = $3,
+    locked_by = $4,
+    locked_at = now(),
+    heartbeat_at = now(),
+    lease_expires_at = now() + $5::interval,
+    attempts = attempts + 1,
+    max_attempts = CASE WHEN $6 > 0 THEN $6 ELSE max_attempts END,
+    updated_at = now()
+WHERE id = (
+  SELECT id
+  FROM jobs
+  WHERE type = $1 AND status = $2 AND run_after <= now()
+  ORDER BY run_after ASC, created_at ASC
+  FOR UPDATE SKIP LOCKED
+  LIMIT 1
+)
+RETURNING id, type, run_id, payload, status, attempts, max_attempts, locked_by, locked_at, heartbeat_at, lease_expires_at, last_error, run_after, created_at, updated_at`
+
+	job, err := scanJob(r.db.QueryRow(ctx, q, jobType, JobStatusQueued, JobStatusRunning, workerID, lease, maxAttempts))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return Job{}, ErrNoJobs
@@ -74,58 +142,366 @@ LIMIT 1`
 		return Job{}, err
 	}
 
-	// Update job to running status
-	now := time.Now()
-	const updateQ = `
+	return job, nil
+}
+
+// ExtendLease renews workerID's lease on a running job and returns the new
+// expiry. It returns ErrLeaseLost when the job is no longer running under
+// workerID, e.g. because the lease expired and the job was reaped.
+func (r *Repo) ExtendLease(ctx context.Context, jobID uuid.UUID, workerID string, lease time.Duration) (time.Time, error) {
+	if lease <= 0 {
+		return time.Time{}, fmt.Errorf("bad input: lease")
+	}
+
+	const q = `
 UPDATE jobs
-SET status = $1,
-    locked_by = $2,
-    locked_at = $3,
-    attempts = attempts + 1,
-    updated_at = $4
-WHERE id = $5`
+SET heartbeat_at = now(),
+    lease_expires_at = now() + $1::interval,
+    updated_at = now()
+WHERE id = $2 AND status = $3 AND locked_by = $4
+RETURNING lease_expires_at`
 
-	_, err = r.db.Exec(ctx, updateQ, JobStatusRunning, workerID, now, now, job.ID)
+	var expires time.Time
+	err := r.db.QueryRow(ctx, q, lease, jobID, JobStatusRunning, workerID).Scan(&expires)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return time.Time{}, ErrLeaseLost
+	}
 	if err != nil {
-		return Job{}, err
+		return time.Time{}, err
 	}
+	return expires, nil
+}
 
-	job.Status = JobStatusRunning
-	job.LockedBy = &workerID
-	job.LockedAt = &now
-	job.Attempts++
-	job.UpdatedAt = now
+// MarkJobDone completes a job held by workerID and records the attempt.
+func (r *Repo) MarkJobDone(ctx context.Context, jobID uuid.UUID, workerID string) error {
+	const q = `
+WITH prev AS (
+  SELECT id, attempts, locked_at
+  FROM jobs
+  WHERE id = $2 AND status = $3 AND locked_by = $4
+  FOR UPDATE
+),
+updated AS (
+  UPDATE jobs j
+  SET status = $1,
+      lease_expires_at = NULL,
+      updated_at = now()
+  FROM prev
+  WHERE j.id = prev.id
+  RETURNING j.id
+)
+INSERT INTO job_attempts (job_id, attempt, worker_id, outcome, started_at)
+SELECT prev.id, prev.attempts, $4, $5, COALESCE(prev.locked_at, now())
+FROM prev JOIN updated ON updated.id = prev.id`
 
-	return job, nil
+	return r.execLeased(ctx, q, JobStatusDone, jobID, JobStatusRunning, workerID, AttemptSucceeded)
 }
 
-func (r *Repo) MarkJobDone(ctx context.Context, jobID uuid.UUID) error {
+// MarkJobFailed fails a job held by workerID for good and records the
+// attempt.
+func (r *Repo) MarkJobFailed(ctx context.Context, jobID uuid.UUID, workerID, errorMsg string) error {
 	const q = `
-UPDATE jobs
-SET status = $1,
-    updated_at = now()
-WHERE id = $2`
+WITH prev AS (
+  SELECT id, attempts, locked_at
+  FROM jobs
+  WHERE id = $2 AND status = $3 AND locked_by = $4
+  FOR UPDATE
+),
+updated AS (
+  UPDATE jobs j
+  SET status = $1,
+      last_error = $5,
+      locked_by = NULL,
+      locked_at = NULL,
+      lease_expires_at = NULL,
+      updated_at = now()
+  FROM prev
+  WHERE j.id = prev.id
+  RETURNING j.id
+)
+INSERT INTO job_attempts (job_id, attempt, worker_id, outcome, error, started_at)
+SELECT prev.id, prev.attempts, $4, $6, $5, COALESCE(prev.locked_at, now())
+FROM prev JOIN updated ON updated.id = prev.id`
 
-	_, err := r.db.Exec(ctx, q, JobStatusDone, jobID)
-	return err
+	return r.execLeased(ctx, q, JobStatusFailed, jobID, JobStatusRunning, workerID, errorMsg, AttemptFailed)
 }
 
-func (r *Repo) MarkJobFailed(ctx context.Context, jobID uuid.UUID, errorMsg string, requeue bool) error {
-	var status string
-	if requeue {
-		status = JobStatusQueued
-	} else {
-		status = JobStatusFailed
+// RetryJob requeues a failed job held by workerID and records the attempt;
+// the job cannot be claimed before runAfter.
+func (r *Repo) RetryJob(ctx context.Context, jobID uuid.UUID, workerID, errorMsg string, runAfter time.Time) error {
+	const q = `
+WITH prev AS (
+  SELECT id, attempts, locked_at
+  FROM jobs
+  WHERE id = $2 AND status = $3 AND locked_by = $4
+  FOR UPDATE
+),
+updated AS (
+  UPDATE jobs j
+  SET status = $1,
+      last_error = $5,
+      run_after = $6,
+      locked_by = NULL,
+      locked_at = NULL,
+      lease_expires_at = NULL,
+      updated_at = now()
+  FROM prev
+  WHERE j.id = prev.id
+  RETURNING j.id
+)
+INSERT INTO job_attempts (job_id, attempt, worker_id, outcome, error, started_at)
+SELECT prev.id, prev.attempts, $4, $7, $5, COALESCE(prev.locked_at, now())
+FROM prev JOIN updated ON updated.id = prev.id`
+
+	return r.execLeased(ctx, q, JobStatusQueued, jobID, JobStatusRunning, workerID, errorMsg, runAfter, AttemptFailed)
+}
+
+// GetJob returns a job by ID.
+func (r *Repo) GetJob(ctx context.Context, jobID uuid.UUID) (Job, error) {
+	const q = `
+SELECT id, type, run_id, payload, status, attempts, max_attempts, locked_by, locked_at, heartbeat_at, lease_expires_at, last_error, run_after, created_at, updated_at
+FROM jobs
+WHERE id = $1`
+
+	job, err := scanJob(r.db.QueryRow(ctx, q, jobID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Job{}, ErrJobNotFound
 	}
+	return job, err
+}
 
+// ListDeadJobs returns failed jobs matching filter, most recently failed
+// first.
+func (r *Repo) ListDeadJobs(ctx context.Context, filter DeadJobFilter, limit, offset int) ([]Job, error) {
 	const q = `
-UPDATE jobs
-SET status = $1,
-    last_error = $2,
+SELECT id, type, run_id, payload, status, attempts, max_attempts, locked_by, locked_at, heartbeat_at, lease_expires_at, last_error, run_after, created_at, updated_at
+FROM jobs
+WHERE status = 'failed'` + deadJobFilterSQL + `
+ORDER BY updated_at DESC, id
+LIMIT $4 OFFSET $5`
+
+	rows, err := r.db.Query(ctx, q, filter.IDs, filter.Type, filter.Error, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	jobs := []Job{}
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, rows.Err()
+}
+
+// ListAttempts returns a job's attempt history, oldest first.
+func (r *Repo) ListAttempts(ctx context.Context, jobID uuid.UUID) ([]Attempt, error) {
+	const q = `
+SELECT id, job_id, attempt, worker_id, outcome, error, started_at, finished_at
+FROM job_attempts
+WHERE job_id = $1
+ORDER BY started_at, attempt`
+
+	rows, err := r.db.Query(ctx, q, jobID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	attempts := []Attempt{}
+	for rows.Next() {
+		var a Attempt
+		if err := rows.Scan(&a.ID, &a.JobID, &a.Attempt, &a.WorkerID, &a.Outcome, &a.Error, &a.StartedAt, &a.FinishedAt); err != nil {
+			return nil, err
+		}
+		attempts = append(attempts, a)
+	}
+	return attempts, rows.Err()
+}
+
+// RequeueDeadJobs moves failed jobs matching filter back to the queue with
+// a fresh set of attempts, and their failed runs back to queued, in one
+// statement. The attempt history is kept.
+func (r *Repo) RequeueDeadJobs(ctx context.Context, filter DeadJobFilter) ([]uuid.UUID, error) {
+	const q = `
+WITH requeued AS (
+  UPDATE jobs
+  SET status = 'queued',
+      attempts = 0,
+      run_after = now(),
+      locked_by = NULL,
+      locked_at = NULL,
+      lease_expires_at = NULL,
+      updated_at = now()
+  WHERE status = 'failed'` + deadJobFilterSQL + `
+  RETURNING id, run_id
+),
+runs_reset AS (
+  UPDATE runs r
+  SET status = 'queued',
+      error_message = NULL,
+      updated_at = now()
+  FROM requeued
+  WHERE r.id = requeued.run_id AND r.status = 'failed'
+)
+SELECT id FROM requeued`
+
+	ids, err := r.collectIDs(ctx, q, filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) > 0 {
+		if err := r.Notify(ctx); err != nil {
+			slog.Warn("failed to notify workers", "error", err)
+		}
+	}
+	return ids, nil
+}
+
+// DiscardDeadJobs deletes failed jobs matching filter along with their
+// attempt history. Their runs stay failed.
+func (r *Repo) DiscardDeadJobs(ctx context.Context, filter DeadJobFilter) ([]uuid.UUID, error) {
+	const q = `
+DELETE FROM jobs
+WHERE status = 'failed'` + deadJobFilterSQL + `
+RETURNING id`
+
+	return r.collectIDs(ctx, q, filter)
+}
+
+// deadJobFilterSQL applies a DeadJobFilter passed as $1 (IDs), $2 (type)
+// and $3 (error substring).
+const deadJobFilterSQL = `
+  AND (COALESCE(cardinality($1::uuid[]), 0) = 0 OR id = ANY($1::uuid[]))
+  AND ($2::text = '' OR type = $2::text)
+  AND ($3::text = '' OR strpos(lower(last_error), lower($3::text)) > 0)`
+
+func (r *Repo) collectIDs(ctx context.Context, q string, filter DeadJobFilter) ([]uuid.UUID, error) {
+	rows, err := r.db.Query(ctx, q, filter.IDs, filter.Type, filter.Error)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []uuid.UUID{}
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func scanJob(row pgx.Row) (Job, error) {
+	var job Job
+	err := row.Scan(
+		&job.ID,
+		&job.Type,
+		&job.RunID,
+		&job.Payload,
+		&job.Status,
+		&job.Attempts,
+		&job.MaxAttempts,
+		&job.LockedBy,
+		&job.LockedAt,
+		&job.HeartbeatAt,
+		&job.LeaseExpiresAt,
+		&job.LastError,
+		&job.RunAfter,
+		&job.CreatedAt,
+		&job.UpdatedAt,
+	)
+	return job, err
+}
+
+// UpdateRunStatus sets the status of the run a job belongs to.
+func (r *Repo) UpdateRunStatus(ctx context.Context, runID uuid.UUID, status string, errorMessage *string) error {
+	const q = `
+UPDATE runs
+SET status = $2,
+    error_message = $3,
     updated_at = now()
-WHERE id = $3`
+WHERE id = $1`
 
-	_, err := r.db.Exec(ctx, q, status, errorMsg, jobID)
+	_, err := r.db.Exec(ctx, q, runID, status, errorMessage)
 	return err
 }
 
+// execLeased runs an update guarded by the caller's lease and returns
+// ErrLeaseLost when it affected no row.
+func (r *Repo) execLeased(ctx context.Context, q string, args ...any) error {
+	tag, err := r.db.Exec(ctx, q, args...)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+// ReapStaleJobs requeues running jobs whose lease expired, or fails them
+// when attempts are exhausted, and moves their runs back to queued or to
+// failed. Stale rows are claimed with SKIP LOCKED and the lease is
+// re-checked, so concurrent reapers never reap the same job twice and a
+// job that finishes or heartbeats meanwhile is left alone.
+func (r *Repo) ReapStaleJobs(ctx context.Context) ([]ReapedJob, error) {
+	const q = `
+WITH stale AS (
+  SELECT id, locked_by, locked_at, lease_expires_at
+  FROM jobs
+  WHERE status = 'running' AND lease_expires_at < now()
+  FOR UPDATE SKIP LOCKED
+),
+reaped AS (
+  UPDATE jobs j
+  SET status = CASE WHEN j.attempts >= j.max_attempts THEN 'failed'::job_status ELSE 'queued'::job_status END,
+      last_error = 'lease held by ' || COALESCE(j.locked_by, 'unknown worker') || ' expired at ' || stale.lease_expires_at,
+      locked_by = NULL,
+      locked_at = NULL,
+      lease_expires_at = NULL,
+      updated_at = now()
+  FROM stale
+  WHERE j.id = stale.id AND j.status = 'running' AND j.lease_expires_at < now()
+  RETURNING j.id, j.type, j.run_id, j.status, stale.locked_by, stale.locked_at, j.attempts, j.last_error
+),
+attempts_logged AS (
+  INSERT INTO job_attempts (job_id, attempt, worker_id, outcome, error, started_at)
+  SELECT id, attempts, COALESCE(locked_by, ''), 'expired', last_error, COALESCE(locked_at, now())
+  FROM reaped
+),
+runs_reset AS (
+  UPDATE runs r
+  SET status = CASE WHEN reaped.status = 'failed' THEN 'failed'::run_status ELSE 'queued'::run_status END,
+      error_message = CASE WHEN reaped.status = 'failed' THEN reaped.last_error ELSE NULL END,
+      updated_at = now()
+  FROM reaped
+  WHERE r.id = reaped.run_id AND r.status IN ('created', 'queued', 'processing')
+)
+SELECT id, type, run_id, status, locked_by, attempts FROM reaped`
+
+	rows, err := r.db.Query(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reaped []ReapedJob
+	for rows.Next() {
+		var job ReapedJob
+		if err := rows.Scan(&job.ID, &job.Type, &job.RunID, &job.Status, &job.LockedBy, &job.Attempts); err != nil {
+			return nil, err
+		}
+		reaped = append(reaped, job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return reaped, nil
+}
@@ -0,0 +1,106 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"resume-tailor/internal/db"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// testDB connects to the migrated database at TEST_DATABASE_URL, skipping
+// the test when it is not set.
+func testDB(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+	databaseURL := os.Getenv("TEST_DATABASE_URL")
+	if databaseURL == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	pool, err := db.Connect(context.Background(), databaseURL)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(func() { db.Close(pool) })
+	return pool
+}
+
+// TestClaimConcurrent runs many claimers against one queue and checks that
+// every job is claimed exactly once.
+func TestClaimConcurrent(t *testing.T) {
+	const (
+		numJobs     = 200
+		numClaimers = 16
+	)
+
+	ctx := context.Background()
+	pool := testDB(t)
+	repo := NewRepo(pool)
+
+	// A type of its own keeps the test away from real jobs in the queue
+	jobType := "test_claim_" + uuid.NewString()
+	t.Cleanup(func() {
+		if _, err := pool.Exec(context.Background(), "DELETE FROM jobs WHERE type = $1", jobType); err != nil {
+			t.Errorf("cleanup: %v", err)
+		}
+	})
+
+	enqueued := make(map[uuid.UUID]bool, numJobs)
+	for range numJobs {
+		id, err := repo.Enqueue(ctx, jobType, nil, EnqueueOptions{})
+		if err != nil {
+			t.Fatalf("enqueue: %v", err)
+		}
+		enqueued[id] = true
+	}
+
+	var (
+		mu       sync.Mutex
+		claims   = make(map[uuid.UUID]int, numJobs)
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		claimErr error
+	)
+	for i := range numClaimers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			workerID := fmt.Sprintf("test-worker-%d", i)
+			for {
+				job, err := repo.Claim(ctx, jobType, workerID, time.Minute, 0)
+				if errors.Is(err, ErrNoJobs) {
+					return
+				}
+				if err != nil {
+					errOnce.Do(func() { claimErr = err })
+					return
+				}
+				mu.Lock()
+				claims[job.ID]++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if claimErr != nil {
+		t.Fatalf("claim: %v", claimErr)
+	}
+	for id := range enqueued {
+		if n := claims[id]; n != 1 {
+			t.Errorf("job %s claimed %d times, want 1", id, n)
+		}
+	}
+	for id := range claims {
+		if !enqueued[id] {
+			t.Errorf("claimed job %s that was not enqueued", id)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"resume-tailor/internal/ai"
+	"resume-tailor/internal/resumes"
+	"resume-tailor/internal/usage"
+)
+
+// Backoff computes the delay before a failed job is retried: Base doubled
+// per attempt and capped at Max, with "equal jitter" (a random delay
+// between half and all of that) so jobs failed by the same outage do not
+// retry in lockstep.
+type Backoff struct {
+	Base time.Duration
+	Max  time.Duration
+}
+
+// DefaultBackoff retries after roughly 10s, 20s, 40s, 80s... up to 10m.
+var DefaultBackoff = Backoff{Base: 10 * time.Second, Max: 10 * time.Minute}
+
+// Delay returns the wait before the next attempt after attempts failures.
+func (b Backoff) Delay(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	d := b.Max
+	if shift := attempts - 1; shift < 32 {
+		if exp := b.Base << shift; exp > 0 && exp < b.Max {
+			d = exp
+		}
+	}
+	half := d / 2
+	return half + rand.N(d-half+1)
+}
+
+// permanentError marks a failure that retrying cannot fix.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps err so the job fails without further attempts.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// retryable reports whether a failed run may succeed on another attempt.
+// Explicitly permanent errors, missing runs or resumes and exhausted
+// budgets fail immediately; report errors classify themselves (rate
+// limits, 5xx and timeouts retry, schema violations after repair do not);
+// anything else, such as database and network errors, is assumed
+// transient.
+func retryable(err error) bool {
+	var permanent *permanentError
+	if errors.As(err, &permanent) {
+		return false
+	}
+	if errors.Is(err, ErrRunNotFound) || errors.Is(err, resumes.ErrResumeNotFound) {
+		return false
+	}
+	if errors.Is(err, usage.ErrBudgetExceeded) {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var reportErr *ai.ReportError
+	if errors.As(err, &reportErr) {
+		return reportErr.Retryable()
+	}
+	return true
+}
@@ -1,30 +1,61 @@
 package jobs
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// Job is a row of the jobs table. RunID is set for jobs that process a run;
+// the run's status follows the job when it is retried, failed or reaped.
 type Job struct {
-	ID         uuid.UUID
-	Type       string
-	RunID      uuid.UUID
-	Status     string
-	Attempts   int
+	ID             uuid.UUID
+	Type           string
+	RunID          *uuid.UUID
+	Payload        json.RawMessage
+	Status         string
+	Attempts       int
+	MaxAttempts    int
+	LockedBy       *string
+	LockedAt       *time.Time
+	HeartbeatAt    *time.Time
+	LeaseExpiresAt *time.Time
+	LastError      *string
+	RunAfter       time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// Decode unmarshals the job's payload into v.
+func (j Job) Decode(v any) error {
+	if err := json.Unmarshal(j.Payload, v); err != nil {
+		return Permanent(fmt.Errorf("bad %s payload: %w", j.Type, err))
+	}
+	return nil
+}
+
+// EnqueueOptions tune a single job. Zero values use the defaults: the job
+// is due immediately and gets DefaultMaxAttempts.
+type EnqueueOptions struct {
+	RunID       *uuid.UUID
 	MaxAttempts int
-	LockedBy   *string
-	LockedAt   *time.Time
-	LastError  *string
-	CreatedAt  time.Time
-	UpdatedAt  time.Time
+	RunAfter    time.Time
 }
 
+const DefaultMaxAttempts = 5
+
 const (
 	JobTypeProcessRun = "process_run"
 )
 
+// ProcessRunPayload is the payload of a process_run job.
+type ProcessRunPayload struct {
+	RunID uuid.UUID `json:"run_id"`
+}
+
 const (
 	JobStatusQueued  = "queued"
 	JobStatusRunning = "running"
@@ -32,8 +63,47 @@ const (
 	JobStatusDone    = "done"
 )
 
+// Attempt is one run of a job by a worker, from claim to outcome.
+type Attempt struct {
+	ID         uuid.UUID
+	JobID      uuid.UUID
+	Attempt    int
+	WorkerID   string
+	Outcome    string
+	Error      *string
+	StartedAt  time.Time
+	FinishedAt time.Time
+}
+
+func (a Attempt) Duration() time.Duration {
+	return a.FinishedAt.Sub(a.StartedAt)
+}
+
+// Attempt outcomes. An attempt expires when its worker's lease ran out and
+// the reaper took the job back.
+const (
+	AttemptSucceeded = "succeeded"
+	AttemptFailed    = "failed"
+	AttemptExpired   = "expired"
+)
+
+// DeadJobFilter selects failed jobs. IDs, Type and Error (a
+// case-insensitive substring of last_error) combine with AND; empty fields
+// match everything.
+type DeadJobFilter struct {
+	IDs   []uuid.UUID
+	Type  string
+	Error string
+}
+
+func (f DeadJobFilter) empty() bool {
+	return len(f.IDs) == 0 && f.Type == "" && f.Error == ""
+}
+
 var (
 	ErrJobNotFound = errors.New("job not found")
 	ErrNoJobs      = errors.New("no jobs available")
+	ErrRunNotFound = errors.New("run not found")
+	ErrLeaseLost   = errors.New("job lease lost")
+	ErrBadInput    = errors.New("bad input")
 )
-
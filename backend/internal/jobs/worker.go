@@ -2,231 +2,382 @@ package jobs
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"sync"
 	"time"
 
-	"resume-tailor/internal/ai"
-	"resume-tailor/internal/resumes"
-	"resume-tailor/internal/runreports"
-	"resume-tailor/internal/scoring/bm25"
-
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-const pollInterval = 1 * time.Second
-
 const (
-	runStatusCreated    = "created"
-	runStatusQueued     = "queued"
-	runStatusProcessing = "processing"
-	runStatusFailed     = "failed"
-	runStatusCompleted  = "completed"
+	// pollInterval is used while the LISTEN connection is down
+	pollInterval = 1 * time.Second
+	// idlePollInterval is the safety-net poll while notifications arrive
+	idlePollInterval = 30 * time.Second
+
+	// DefaultLease is how long a claimed job stays locked without a heartbeat
+	DefaultLease = 2 * time.Minute
+	// MinLease leaves heartbeats, sent every third of the lease, time to
+	// renew it before it runs out
+	MinLease = 3 * time.Second
+
+	// failJobTimeout bounds the writes that fail or requeue a cancelled job
+	failJobTimeout = 5 * time.Second
 )
 
-// RunsRepo is an interface to avoid import cycle with runs package
-type RunsRepo interface {
-	GetRunByID(ctx context.Context, runID uuid.UUID) (RunData, error)
-}
+// Worker claims and runs jobs of every type in its registry, each type in
+// its own set of slots, retrying failures with backoff.
+type Worker struct {
+	jobsRepo *Repo
+	db       *pgxpool.Pool
+	workerID string
+	registry *Registry
 
-// RunData represents the run data needed by the worker
-type RunData struct {
-	ID           uuid.UUID
-	ResumeID     uuid.UUID
-	JobText      string
-	Status       string
-	ErrorMessage *string
-}
+	backoff Backoff
 
-type Worker struct {
-	jobsRepo    *Repo
-	db          *pgxpool.Pool
-	workerID    string
-	reportsSvc  *runreports.Service
-	runsRepo    RunsRepo
-	resumesRepo *resumes.Repo
-	aiClient    *ai.Client
+	concurrency     int
+	shutdownTimeout time.Duration
+
+	// lease is how long a claimed job stays locked without a heartbeat
+	lease time.Duration
 }
 
-func NewWorker(jobsRepo *Repo, db *pgxpool.Pool, workerID string, reportsSvc *runreports.Service, runsRepo RunsRepo, resumesRepo *resumes.Repo, aiClient *ai.Client) *Worker {
+func NewWorker(jobsRepo *Repo, db *pgxpool.Pool, workerID string, registry *Registry) *Worker {
 	return &Worker{
-		jobsRepo:    jobsRepo,
-		db:          db,
-		workerID:    workerID,
-		reportsSvc:  reportsSvc,
-		runsRepo:    runsRepo,
-		resumesRepo: resumesRepo,
-		aiClient:    aiClient,
+		jobsRepo: jobsRepo,
+		db:       db,
+		workerID: workerID,
+		registry: registry,
+		backoff:  DefaultBackoff,
+
+		concurrency:     1,
+		shutdownTimeout: 60 * time.Second,
+		lease:           DefaultLease,
 	}
 }
 
+// WithConcurrency sets how many jobs of each type the worker runs at once,
+// for types registered without their own Concurrency. Each slot claims
+// jobs under its own worker ID, derived from the worker's.
+func (w *Worker) WithConcurrency(n int) *Worker {
+	w.concurrency = max(n, 1)
+	return w
+}
+
+// WithLease sets how long a claimed job stays locked to this worker
+// without a heartbeat. Heartbeats renew it every third of that. Leases
+// shorter than MinLease are raised to it.
+func (w *Worker) WithLease(d time.Duration) *Worker {
+	w.lease = max(d, MinLease)
+	return w
+}
+
+// WithShutdownTimeout sets how long in-flight jobs may keep running once
+// Run's context is cancelled before they are cancelled too.
+func (w *Worker) WithShutdownTimeout(d time.Duration) *Worker {
+	w.shutdownTimeout = d
+	return w
+}
+
+// Run processes jobs in slots until ctx is cancelled; every registered
+// type gets its own slots, so a slow type cannot starve the others. A slot
+// sleeps until a NOTIFY on NotifyChannel or the next scheduled retry, then
+// drains every due job. Polling every pollInterval only takes over while
+// the listener connection is down; with it up, the queue is still checked
+// every idlePollInterval in case a notification was lost.
+//
+// Cancelling ctx stops slots from claiming new jobs. Jobs already running
+// get shutdownTimeout to finish, after which their contexts are cancelled
+// and they are requeued.
 func (w *Worker) Run(ctx context.Context) error {
-	slog.Info("worker started", "worker_id", w.workerID)
+	type slot struct {
+		workerID string
+		reg      registration
+		wake     chan struct{}
+	}
+	var slots []slot
+	for _, reg := range w.registry.handlers {
+		n := reg.opts.Concurrency
+		if n <= 0 {
+			n = w.concurrency
+		}
+		for range n {
+			slots = append(slots, slot{reg: reg, wake: make(chan struct{}, 1)})
+		}
+	}
+	if len(slots) == 0 {
+		return fmt.Errorf("no job handlers registered")
+	}
+	for i := range slots {
+		slots[i].workerID = w.workerID
+		if len(slots) > 1 {
+			slots[i].workerID = fmt.Sprintf("%s-%d", w.workerID, i+1)
+		}
+	}
 
-	ticker := time.NewTicker(pollInterval)
-	defer ticker.Stop()
+	slog.Info("worker started", "worker_id", w.workerID, "types", w.registry.Types(), "slots", len(slots))
+
+	// Jobs run on a context that outlives ctx so they can finish during
+	// shutdown; cancelJobs is the hard stop
+	jobsCtx, cancelJobs := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelJobs()
+
+	wake := make(chan struct{}, 1)
+	listener := NewListener(w.db)
+	go listener.Run(ctx, wake)
+
+	// Every notification wakes every idle slot; those that lose the race
+	// for the job find nothing to claim and go back to sleep
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-wake:
+				for _, sl := range slots {
+					signal(sl.wake)
+				}
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for _, sl := range slots {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.runSlot(ctx, jobsCtx, sl.reg, sl.workerID, sl.wake, listener)
+		}()
+	}
+
+	<-ctx.Done()
+	slog.Info("worker stopping, waiting for in-flight jobs", "worker_id", w.workerID, "timeout", w.shutdownTimeout)
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(w.shutdownTimeout):
+		slog.Warn("shutdown timeout reached, cancelling in-flight jobs", "worker_id", w.workerID)
+		cancelJobs()
+		<-done
+	}
+	return ctx.Err()
+}
+
+// runSlot is one concurrency slot for reg's job type. It stops claiming
+// jobs when ctx is cancelled; the job it is running continues on its own
+// context derived from jobsCtx.
+func (w *Worker) runSlot(ctx, jobsCtx context.Context, reg registration, workerID string, wake <-chan struct{}, listener *Listener) {
+	slotCtx, cancel := context.WithCancel(jobsCtx)
+	defer cancel()
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
 
 	for {
 		select {
 		case <-ctx.Done():
-			slog.Info("worker stopping", "worker_id", w.workerID)
-			return ctx.Err()
-		case <-ticker.C:
-			if err := w.processNextJob(ctx); err != nil {
-				if err == ErrNoJobs {
-					// No jobs available, continue polling
-					continue
-				}
-				slog.Error("error processing job", "error", err, "worker_id", w.workerID)
+			return
+		case <-wake:
+		case <-timer.C:
+		}
+
+		w.drain(ctx, slotCtx, reg, workerID)
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
 			}
 		}
+		timer.Reset(w.nextWait(ctx, reg.jobType, listener.Connected()))
 	}
 }
 
-func (w *Worker) processNextJob(ctx context.Context) error {
-	// Claim next job
-	job, err := w.jobsRepo.ClaimNextProcessRun(ctx, w.workerID)
-	if err != nil {
-		return err
+// drain processes jobs back to back until none are due or ctx is cancelled.
+func (w *Worker) drain(ctx, jobCtx context.Context, reg registration, workerID string) {
+	for ctx.Err() == nil {
+		err := w.processNextJob(ctx, jobCtx, reg, workerID)
+		if errors.Is(err, ErrNoJobs) {
+			return
+		}
+		if err != nil {
+			if ctx.Err() == nil {
+				slog.Error("error processing job", "error", err, "worker_id", workerID)
+			}
+			return
+		}
 	}
+}
 
-	slog.Info("claimed job", "job_id", job.ID, "run_id", job.RunID, "worker_id", w.workerID)
-
-	// Update run status to processing
-	if err := w.updateRunStatus(ctx, job.RunID, runStatusProcessing, nil); err != nil {
-		slog.Error("failed to update run status to processing", "error", err, "run_id", job.RunID)
-		// Mark job as failed
-		w.jobsRepo.MarkJobFailed(ctx, job.ID, fmt.Sprintf("failed to update run status: %v", err), job.Attempts < job.MaxAttempts)
-		return err
+// nextWait is how long to sleep before checking the queue for jobType
+// again: until its earliest scheduled retry, capped by the polling interval.
+func (w *Worker) nextWait(ctx context.Context, jobType string, listening bool) time.Duration {
+	wait := pollInterval
+	if listening {
+		wait = idlePollInterval
 	}
 
-	// Process the run (MVP stub)
-	if err := w.processRun(ctx, job.RunID); err != nil {
-		slog.Error("failed to process run", "error", err, "run_id", job.RunID)
-		errorMsg := err.Error()
-
-		// Update run status to failed
-		if err := w.updateRunStatus(ctx, job.RunID, runStatusFailed, &errorMsg); err != nil {
-			slog.Error("failed to update run status to failed", "error", err, "run_id", job.RunID)
+	next, ok, err := w.jobsRepo.NextRunAfter(ctx, jobType)
+	if err != nil {
+		if ctx.Err() == nil {
+			slog.Warn("failed to look up next scheduled job", "error", err)
 		}
+		return pollInterval
+	}
+	if ok {
+		wait = min(wait, max(time.Until(next), 0))
+	}
+	return wait
+}
 
-		// Update job status
-		requeue := job.Attempts < job.MaxAttempts
-		if err := w.jobsRepo.MarkJobFailed(ctx, job.ID, errorMsg, requeue); err != nil {
-			slog.Error("failed to mark job as failed", "error", err, "job_id", job.ID)
-		}
+// processNextJob claims a job on ctx and runs it on jobCtx, so a claimed
+// job is not abandoned halfway when the worker starts shutting down. The
+// job's lease is extended by a heartbeat while it runs; if the lease is
+// lost the job is aborted and left to whichever worker holds it now.
+func (w *Worker) processNextJob(ctx, jobCtx context.Context, reg registration, workerID string) error {
+	// Claim next job
+	job, err := w.jobsRepo.Claim(ctx, reg.jobType, workerID, w.lease, reg.opts.MaxAttempts)
+	if err != nil {
 		return err
 	}
 
-	// Success: update run status to completed
-	if err := w.updateRunStatus(ctx, job.RunID, runStatusCompleted, nil); err != nil {
-		slog.Error("failed to update run status to completed", "error", err, "run_id", job.RunID)
-		w.jobsRepo.MarkJobFailed(ctx, job.ID, fmt.Sprintf("failed to update run status: %v", err), false)
+	slog.Info("claimed job", "job_id", job.ID, "type", job.Type, "run_id", runIDAttr(job.RunID), "attempt", job.Attempts, "worker_id", workerID)
+
+	runCtx, abort := context.WithCancelCause(jobCtx)
+	defer abort(nil)
+	stopHeartbeat := w.heartbeat(runCtx, abort, job, workerID)
+
+	err = reg.handler.Handle(runCtx, job)
+	stopHeartbeat()
+
+	if errors.Is(context.Cause(runCtx), ErrLeaseLost) {
+		slog.Warn("lost job lease, abandoning job", "job_id", job.ID, "type", job.Type, "worker_id", workerID)
+		return nil
+	}
+	if err != nil {
+		slog.Error("job failed", "error", err, "job_id", job.ID, "type", job.Type, "attempt", job.Attempts)
+		w.failJob(jobCtx, job, workerID, err)
 		return err
 	}
 
 	// Mark job as done
-	if err := w.jobsRepo.MarkJobDone(ctx, job.ID); err != nil {
+	if err := w.jobsRepo.MarkJobDone(jobCtx, job.ID, workerID); err != nil {
 		slog.Error("failed to mark job as done", "error", err, "job_id", job.ID)
 		return err
 	}
 
-	slog.Info("job completed", "job_id", job.ID, "run_id", job.RunID, "worker_id", w.workerID)
+	slog.Info("job completed", "job_id", job.ID, "type", job.Type, "worker_id", workerID)
 	return nil
 }
 
-func (w *Worker) processRun(ctx context.Context, runID uuid.UUID) error {
-	// Check if AI client is available
-	if w.aiClient == nil {
-		return fmt.Errorf("OPENAI_API_KEY missing")
+// heartbeat extends the job's lease every third of the lease duration
+// until the returned stop function is called. It aborts ctx with
+// ErrLeaseLost when another worker took the job over, or when renewals
+// keep failing until the lease has run out.
+func (w *Worker) heartbeat(ctx context.Context, abort context.CancelCauseFunc, job Job, workerID string) (stop func()) {
+	hbCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	expires := time.Now().Add(w.lease)
+	if job.LeaseExpiresAt != nil {
+		expires = *job.LeaseExpiresAt
 	}
 
-	// 1. Load the run
-	runData, err := w.runsRepo.GetRunByID(ctx, runID)
-	if err != nil {
-		return fmt.Errorf("failed to load run: %w", err)
-	}
+	go func() {
+		defer close(done)
 
-	// 2. Load the resume
-	resume, err := w.resumesRepo.GetResumeByID(ctx, runData.ResumeID)
-	if err != nil {
-		return fmt.Errorf("failed to load resume: %w", err)
-	}
+		ticker := time.NewTicker(w.lease / 3)
+		defer ticker.Stop()
 
-	resumeText := resume.ContentText
-	jobText := runData.JobText
+		for {
+			select {
+			case <-hbCtx.Done():
+				return
+			case <-ticker.C:
+			}
 
-	// 3. Compute BM25 signals (stub for now)
-	bm25Signals, err := bm25.Compute(resumeText, jobText)
-	if err != nil {
-		slog.Warn("BM25 computation failed, continuing without signals", "error", err, "run_id", runID)
-		bm25Signals = nil
-	}
+			next, err := w.jobsRepo.ExtendLease(hbCtx, job.ID, workerID, w.lease)
+			switch {
+			case err == nil:
+				expires = next
+			case errors.Is(err, ErrLeaseLost):
+				abort(ErrLeaseLost)
+				return
+			case hbCtx.Err() != nil:
+				return
+			case time.Now().After(expires):
+				slog.Error("failed to extend job lease before it expired", "error", err, "job_id", job.ID, "worker_id", workerID)
+				abort(ErrLeaseLost)
+				return
+			default:
+				slog.Warn("failed to extend job lease", "error", err, "job_id", job.ID, "worker_id", workerID, "lease_expires_at", expires)
+			}
+		}
+	}()
 
-	// 4. Generate ATS report and change plan via OpenAI
-	atsReport, changePlan, err := w.aiClient.GenerateRunReport(ctx, resumeText, jobText, bm25Signals)
-	if err != nil {
-		return fmt.Errorf("failed to generate run report: %w", err)
+	return func() {
+		cancel()
+		<-done
 	}
+}
 
-	// 5. Marshal to JSON
-	atsReportJSON, err := json.Marshal(atsReport)
-	if err != nil {
-		return fmt.Errorf("failed to marshal ATS report: %w", err)
+// failJob schedules another attempt after a backoff delay when the error
+// is transient and attempts remain; otherwise the job fails. A job's run
+// follows it to queued or failed and keeps the error message either way.
+// The job is updated first so a worker that lost its lease leaves the run
+// alone.
+func (w *Worker) failJob(ctx context.Context, job Job, workerID string, err error) {
+	errorMsg := err.Error()
+
+	// A job cancelled at shutdown still needs its bookkeeping written
+	if ctx.Err() != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.WithoutCancel(ctx), failJobTimeout)
+		defer cancel()
 	}
 
-	changePlanJSON, err := json.Marshal(changePlan)
-	if err != nil {
-		return fmt.Errorf("failed to marshal change plan: %w", err)
-	}
+	if job.Attempts < job.MaxAttempts && retryable(err) {
+		delay := w.backoff.Delay(job.Attempts)
+		slog.Warn("retrying job", "job_id", job.ID, "type", job.Type, "attempt", job.Attempts, "max_attempts", job.MaxAttempts, "retry_in", delay)
 
-	// 6. Persist into run_reports
-	if w.reportsSvc != nil {
-		if err := w.reportsSvc.UpsertRunReport(ctx, runID, atsReportJSON, changePlanJSON); err != nil {
-			return fmt.Errorf("failed to upsert run report: %w", err)
+		if err := w.jobsRepo.RetryJob(ctx, job.ID, workerID, errorMsg, time.Now().Add(delay)); err != nil {
+			slog.Error("failed to requeue job", "error", err, "job_id", job.ID)
+			return
 		}
+		w.setRunStatus(ctx, job, runStatusQueued, errorMsg)
+		return
 	}
 
-	// Placeholder JSON for artifacts (LaTeX/PDF generation not implemented yet)
-	resumeSpec := map[string]interface{}{
-		"version":   "1.0",
-		"sections":  []string{"placeholder section"},
-		"timestamp": time.Now().Unix(),
+	if err := w.jobsRepo.MarkJobFailed(ctx, job.ID, workerID, errorMsg); err != nil {
+		slog.Error("failed to mark job as failed", "error", err, "job_id", job.ID)
+		return
 	}
+	w.setRunStatus(ctx, job, runStatusFailed, errorMsg)
+}
 
-	resumeSpecJSON, err := json.Marshal(resumeSpec)
-	if err != nil {
-		return fmt.Errorf("failed to marshal resume spec: %w", err)
+// setRunStatus moves the run a failed job belongs to, if any.
+func (w *Worker) setRunStatus(ctx context.Context, job Job, status, errorMsg string) {
+	if job.RunID == nil {
+		return
 	}
-
-	// Insert into run_artifacts
-	const insertArtifactQ = `
-INSERT INTO run_artifacts (run_id, resume_spec, latex_path, pdf_path)
-VALUES ($1, $2, $3, $4)
-ON CONFLICT (run_id) DO UPDATE
-SET resume_spec = $2, latex_path = $3, pdf_path = $4, created_at = now()`
-
-	latexPath := fmt.Sprintf("/generated/%s/resume.tex", runID.String())
-	pdfPath := fmt.Sprintf("/generated/%s/resume.pdf", runID.String())
-
-	_, err = w.db.Exec(ctx, insertArtifactQ, runID, resumeSpecJSON, latexPath, pdfPath)
-	if err != nil {
-		return fmt.Errorf("failed to insert run artifact: %w", err)
+	if err := w.jobsRepo.UpdateRunStatus(ctx, *job.RunID, status, &errorMsg); err != nil {
+		slog.Error("failed to update run status", "error", err, "run_id", *job.RunID, "status", status)
 	}
-
-	return nil
 }
 
-func (w *Worker) updateRunStatus(ctx context.Context, runID uuid.UUID, status string, errorMessage *string) error {
-	const q = `
-UPDATE runs
-SET status = $2,
-    error_message = $3,
-    updated_at = now()
-WHERE id = $1`
-
-	_, err := w.db.Exec(ctx, q, runID, status, errorMessage)
-	return err
+// runIDAttr formats an optional run ID for logging.
+func runIDAttr(runID *uuid.UUID) string {
+	if runID == nil {
+		return ""
+	}
+	return runID.String()
 }
@@ -0,0 +1,99 @@
+// Package llmcache stores validated LLM report replies in Postgres,
+// keyed by a hash of their normalized inputs.
+package llmcache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"resume-tailor/internal/ai"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repo implements ai.ReportCache. Entries live for ttl after they are
+// written; reads do not extend them.
+type Repo struct {
+	db  *pgxpool.Pool
+	ttl time.Duration
+}
+
+func NewRepo(db *pgxpool.Pool, ttl time.Duration) *Repo {
+	return &Repo{db: db, ttl: ttl}
+}
+
+// Get returns an unexpired entry and counts the hit.
+func (r *Repo) Get(ctx context.Context, key string) (ai.CachedReply, bool, error) {
+	if key == "" {
+		return ai.CachedReply{}, false, fmt.Errorf("bad input: key")
+	}
+
+	const q = `
+UPDATE llm_cache
+SET hits = hits + 1, last_hit_at = now()
+WHERE key = $1 AND expires_at > now()
+RETURNING provider, model, prompt_version, schema_version, temperature, content, input_tokens, output_tokens`
+
+	var reply ai.CachedReply
+	err := r.db.QueryRow(ctx, q, key).Scan(
+		&reply.Provider,
+		&reply.Model,
+		&reply.PromptVersion,
+		&reply.SchemaVersion,
+		&reply.Temperature,
+		&reply.Content,
+		&reply.InputTokens,
+		&reply.OutputTokens,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ai.CachedReply{}, false, nil
+		}
+		return ai.CachedReply{}, false, err
+	}
+
+	return reply, true, nil
+}
+
+// Put stores or replaces an entry and restarts its TTL.
+func (r *Repo) Put(ctx context.Context, key string, reply ai.CachedReply) error {
+	if key == "" {
+		return fmt.Errorf("bad input: key")
+	}
+
+	const q = `
+INSERT INTO llm_cache (key, provider, model, prompt_version, schema_version, temperature, content, input_tokens, output_tokens, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, now() + $10::interval)
+ON CONFLICT (key) DO UPDATE
+SET provider = $2, model = $3, prompt_version = $4, schema_version = $5, temperature = $6,
+    content = $7, input_tokens = $8, output_tokens = $9,
+    hits = 0, last_hit_at = NULL, created_at = now(), expires_at = now() + $10::interval`
+
+	_, err := r.db.Exec(ctx, q,
+		key,
+		reply.Provider,
+		reply.Model,
+		reply.PromptVersion,
+		reply.SchemaVersion,
+		reply.Temperature,
+		reply.Content,
+		reply.InputTokens,
+		reply.OutputTokens,
+		r.ttl,
+	)
+	return err
+}
+
+// DeleteExpired removes expired entries and returns how many were deleted.
+func (r *Repo) DeleteExpired(ctx context.Context) (int64, error) {
+	const q = `DELETE FROM llm_cache WHERE expires_at <= now()`
+
+	cmdTag, err := r.db.Exec(ctx, q)
+	if err != nil {
+		return 0, err
+	}
+	return cmdTag.RowsAffected(), nil
+}
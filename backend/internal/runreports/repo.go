@@ -2,7 +2,6 @@ package runreports
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 
@@ -19,18 +18,31 @@ func NewRepo(db *pgxpool.Pool) *Repo {
 	return &Repo{db: db}
 }
 
-func (r *Repo) UpsertRunReport(ctx context.Context, runID uuid.UUID, atsReport, changePlan json.RawMessage) error {
-	if runID == uuid.Nil {
+func (r *Repo) UpsertRunReport(ctx context.Context, report RunReport) error {
+	if report.RunID == uuid.Nil {
 		return fmt.Errorf("bad input: run_id")
 	}
 
 	const q = `
-INSERT INTO run_reports (run_id, ats_report, change_plan)
-VALUES ($1, $2, $3)
+INSERT INTO run_reports (run_id, ats_report, change_plan, mode, prompt_version, model, provider, schema_version, cache_key, cache_hit)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 ON CONFLICT (run_id) DO UPDATE
-SET ats_report = $2, change_plan = $3, created_at = now()`
+SET ats_report = $2, change_plan = $3, mode = $4,
+    prompt_version = $5, model = $6, provider = $7, schema_version = $8,
+    cache_key = $9, cache_hit = $10, created_at = now()`
 
-	_, err := r.db.Exec(ctx, q, runID, atsReport, changePlan)
+	_, err := r.db.Exec(ctx, q,
+		report.RunID,
+		report.ATSReport,
+		report.ChangePlan,
+		report.Mode,
+		report.PromptVersion,
+		report.Model,
+		report.Provider,
+		report.SchemaVersion,
+		report.CacheKey,
+		report.CacheHit,
+	)
 	if err != nil {
 		return err
 	}
@@ -44,7 +56,7 @@ func (r *Repo) GetRunReportByRunID(ctx context.Context, runID uuid.UUID) (RunRep
 	}
 
 	const q = `
-SELECT run_id, ats_report, change_plan, created_at
+SELECT run_id, ats_report, change_plan, mode, created_at, prompt_version, model, provider, schema_version, cache_key, cache_hit
 FROM run_reports
 WHERE run_id = $1`
 
@@ -53,7 +65,14 @@ WHERE run_id = $1`
 		&report.RunID,
 		&report.ATSReport,
 		&report.ChangePlan,
+		&report.Mode,
 		&report.CreatedAt,
+		&report.PromptVersion,
+		&report.Model,
+		&report.Provider,
+		&report.SchemaVersion,
+		&report.CacheKey,
+		&report.CacheHit,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
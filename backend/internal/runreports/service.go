@@ -2,7 +2,6 @@ package runreports
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 
 	"github.com/google/uuid"
@@ -24,11 +23,21 @@ func (s *Service) GetRunReportByRunID(ctx context.Context, runID uuid.UUID) (Run
 	return s.repo.GetRunReportByRunID(ctx, runID)
 }
 
-func (s *Service) UpsertRunReport(ctx context.Context, runID uuid.UUID, atsReport, changePlan json.RawMessage) error {
-	if runID == uuid.Nil {
+func (s *Service) UpsertRunReport(ctx context.Context, report RunReport) error {
+	if report.RunID == uuid.Nil {
 		return fmt.Errorf("bad input: run_id")
 	}
 
-	return s.repo.UpsertRunReport(ctx, runID, atsReport, changePlan)
+	switch report.Mode {
+	case ModeLLM, ModeDeterministic:
+	default:
+		return fmt.Errorf("bad input: mode")
+	}
+
+	if report.SchemaVersion < 1 {
+		return fmt.Errorf("bad input: schema_version")
+	}
+
+	return s.repo.UpsertRunReport(ctx, report)
 }
 
@@ -8,15 +8,36 @@ import (
 	"github.com/google/uuid"
 )
 
+// Mode records how a report was generated.
+type Mode string
+
+const (
+	ModeLLM           Mode = "llm"
+	ModeDeterministic Mode = "deterministic"
+)
+
 type RunReport struct {
 	RunID      uuid.UUID
 	ATSReport  json.RawMessage
 	ChangePlan json.RawMessage
-	CreatedAt  time.Time
+	Mode       Mode
+
+	// SchemaVersion is the version of the ATSReport/ChangePlan JSON shape
+	SchemaVersion int
+	CreatedAt     time.Time
+
+	// Set for LLM reports only
+	PromptVersion *string
+	Model         *string
+	Provider      *string
+
+	// CacheKey is the LLM cache entry the report was stored under or, when
+	// CacheHit is set, served from
+	CacheKey *string
+	CacheHit bool
 }
 
 var (
 	ErrRunReportNotFound = errors.New("run report not found")
 	ErrBadInput          = errors.New("bad input")
 )
-
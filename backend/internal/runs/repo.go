@@ -19,21 +19,22 @@ func NewRepo(db *pgxpool.Pool) *Repo {
 	return &Repo{db: db}
 }
 
-func (r *Repo) CreateRun(ctx context.Context, userID, resumeID uuid.UUID, jobText string) (Run, error) {
+func (r *Repo) CreateRun(ctx context.Context, userID, resumeID uuid.UUID, jobText string, bypassCache bool) (Run, error) {
 	const q = `
-INSERT INTO runs (user_id, resume_id, job_text, status)
-VALUES ($1, $2, $3, $4)
-RETURNING id, user_id, resume_id, job_text, status, error_message, created_at, updated_at
+INSERT INTO runs (user_id, resume_id, job_text, status, bypass_cache)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, user_id, resume_id, job_text, status, error_message, bypass_cache, created_at, updated_at
 `
 
 	var run Run
-	err := r.db.QueryRow(ctx, q, userID, resumeID, jobText, StatusCreated).Scan(
+	err := r.db.QueryRow(ctx, q, userID, resumeID, jobText, StatusCreated, bypassCache).Scan(
 		&run.ID,
 		&run.UserID,
 		&run.ResumeID,
 		&run.JobText,
 		&run.Status,
 		&run.ErrorMessage,
+		&run.BypassCache,
 		&run.CreatedAt,
 		&run.UpdatedAt,
 	)
@@ -50,7 +51,7 @@ func (r *Repo) GetRunByID(ctx context.Context, runID uuid.UUID) (Run, error) {
 
 	}
 	const q = `
-		SELECT id, user_id, resume_id, job_text, status, error_message, created_at, updated_at 
+		SELECT id, user_id, resume_id, job_text, status, error_message, bypass_cache, created_at, updated_at 
 		FROM runs where id = $1`
 
 	var run Run
@@ -61,6 +62,7 @@ func (r *Repo) GetRunByID(ctx context.Context, runID uuid.UUID) (Run, error) {
 		&run.JobText,
 		&run.Status,
 		&run.ErrorMessage,
+		&run.BypassCache,
 		&run.CreatedAt,
 		&run.UpdatedAt,
 	)
@@ -91,7 +93,7 @@ func (r *Repo) ListRunsByUser(ctx context.Context, userID uuid.UUID, limit, offs
 	}
 
 	const q = `
-SELECT id, user_id, resume_id, job_text, status, error_message, created_at, updated_at
+SELECT id, user_id, resume_id, job_text, status, error_message, bypass_cache, created_at, updated_at
 FROM runs
 WHERE user_id = $1
 ORDER BY created_at DESC
@@ -113,6 +115,7 @@ LIMIT $2 OFFSET $3`
 			&run.JobText,
 			&run.Status,
 			&run.ErrorMessage,
+			&run.BypassCache,
 			&run.CreatedAt,
 			&run.UpdatedAt,
 		); err != nil {
@@ -24,7 +24,7 @@ func NewService(repo *Repo, jobsEnq jobs.JobsEnqueuer) *Service {
 }
 
 func (s *Service) CreateRun(ctx context.Context, userID,
-	resumeID uuid.UUID, jobText string) (Run, error) {
+	resumeID uuid.UUID, jobText string, bypassCache bool) (Run, error) {
 
 	if userID == uuid.Nil {
 		return Run{}, fmt.Errorf("bad input: user_id")
@@ -41,7 +41,7 @@ func (s *Service) CreateRun(ctx context.Context, userID,
 
 	jobText = strings.TrimSpace(jobText)
 
-	run, err := s.repo.CreateRun(ctx, userID, resumeID, jobText)
+	run, err := s.repo.CreateRun(ctx, userID, resumeID, jobText, bypassCache)
 	if err != nil {
 		return Run{}, err
 	}
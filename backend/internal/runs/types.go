@@ -26,6 +26,9 @@ type Run struct {
 	ErrorMessage *string
 	CreatedAt    time.Time
 	UpdatedAt    time.Time
+
+	// BypassCache forces a fresh LLM call instead of a cached reply
+	BypassCache bool
 }
 
 var (
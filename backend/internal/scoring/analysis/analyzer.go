@@ -0,0 +1,80 @@
+package analysis
+
+import "strings"
+
+// Token is a single analyzed unit of text. Term is the normalized form used
+// for matching; Surface keeps the original spelling for display. Keyword
+// tokens (e.g. canonical skills) are not stemmed or dropped by later filters.
+type Token struct {
+	Term     string
+	Surface  string
+	Position int
+	Keyword  bool
+}
+
+// Phrase reports whether the token was produced by n-gram extraction.
+func (t Token) Phrase() bool {
+	return strings.Contains(t.Term, " ")
+}
+
+// Tokenizer splits raw text into tokens.
+type Tokenizer interface {
+	Tokenize(text string) []Token
+}
+
+// Filter transforms a token stream (lowercasing, stopwords, stemming...).
+type Filter interface {
+	Filter(tokens []Token) []Token
+}
+
+// FilterFunc adapts a plain function to the Filter interface.
+type FilterFunc func(tokens []Token) []Token
+
+func (f FilterFunc) Filter(tokens []Token) []Token {
+	return f(tokens)
+}
+
+// Analyzer is a tokenizer followed by an ordered chain of filters. Resume
+// and job text must go through the same Analyzer so terms are comparable.
+type Analyzer struct {
+	tokenizer Tokenizer
+	filters   []Filter
+}
+
+func New(tokenizer Tokenizer, filters ...Filter) *Analyzer {
+	return &Analyzer{tokenizer: tokenizer, filters: filters}
+}
+
+// Default returns the analyzer used for scoring: tech-aware tokenization,
+// lowercasing, English stopwords, Porter stemming and bigram/trigram phrases.
+// Normalizers (such as skill synonym expansion) run right after lowercasing.
+func Default(normalizers ...Filter) *Analyzer {
+	filters := []Filter{LowercaseFilter()}
+	filters = append(filters, normalizers...)
+	filters = append(filters,
+		StopwordFilter(EnglishStopwords),
+		MinLengthFilter(2),
+		StemFilter(),
+		NGramFilter(2, 3),
+	)
+	return New(NewStandardTokenizer(), filters...)
+}
+
+// Analyze runs text through the tokenizer and every filter in order.
+func (a *Analyzer) Analyze(text string) []Token {
+	tokens := a.tokenizer.Tokenize(text)
+	for _, f := range a.filters {
+		tokens = f.Filter(tokens)
+	}
+	return tokens
+}
+
+// Terms is Analyze without position and surface information.
+func (a *Analyzer) Terms(text string) []string {
+	tokens := a.Analyze(text)
+	terms := make([]string, len(tokens))
+	for i, t := range tokens {
+		terms[i] = t.Term
+	}
+	return terms
+}
@@ -0,0 +1,117 @@
+package analysis
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// LowercaseFilter folds every term to lower case.
+func LowercaseFilter() Filter {
+	return FilterFunc(func(tokens []Token) []Token {
+		for i := range tokens {
+			tokens[i].Term = strings.ToLower(tokens[i].Term)
+		}
+		return tokens
+	})
+}
+
+// StopwordFilter drops tokens whose term is in the given set. Positions are
+// left untouched so phrases never bridge a removed word.
+func StopwordFilter(stopwords map[string]struct{}) Filter {
+	return FilterFunc(func(tokens []Token) []Token {
+		out := tokens[:0]
+		for _, t := range tokens {
+			if _, ok := stopwords[t.Term]; ok && !t.Keyword {
+				continue
+			}
+			out = append(out, t)
+		}
+		return out
+	})
+}
+
+// MinLengthFilter drops terms shorter than min runes. Single uppercase
+// letters are kept since they are usually language names (C, R).
+func MinLengthFilter(min int) Filter {
+	return FilterFunc(func(tokens []Token) []Token {
+		out := tokens[:0]
+		for _, t := range tokens {
+			if utf8.RuneCountInString(t.Term) < min && !t.Keyword && !isSingleUpper(t.Surface) {
+				continue
+			}
+			out = append(out, t)
+		}
+		return out
+	})
+}
+
+func isSingleUpper(s string) bool {
+	r, size := utf8.DecodeRuneInString(s)
+	return size == len(s) && unicode.IsUpper(r)
+}
+
+// StemFilter applies the Porter stemmer to plain alphabetic terms. Terms
+// with digits or connectors (c++, node.js, ci/cd) are left as-is.
+func StemFilter() Filter {
+	return FilterFunc(func(tokens []Token) []Token {
+		for i := range tokens {
+			if !tokens[i].Keyword && isASCIIWord(tokens[i].Term) {
+				tokens[i].Term = Stem(tokens[i].Term)
+			}
+		}
+		return tokens
+	})
+}
+
+func isASCIIWord(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < 'a' || s[i] > 'z' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// NGramFilter appends phrase tokens built from runs of adjacent terms, from
+// min to max words long. The original unigrams are kept.
+func NGramFilter(min, max int) Filter {
+	return FilterFunc(func(tokens []Token) []Token {
+		if min < 2 || max < min {
+			return tokens
+		}
+
+		out := make([]Token, len(tokens), len(tokens)*2)
+		copy(out, tokens)
+
+		for n := min; n <= max; n++ {
+			for i := 0; i+n <= len(tokens); i++ {
+				window := tokens[i : i+n]
+				if !contiguous(window) {
+					continue
+				}
+				terms := make([]string, n)
+				surfaces := make([]string, n)
+				for j, t := range window {
+					terms[j] = t.Term
+					surfaces[j] = t.Surface
+				}
+				out = append(out, Token{
+					Term:     strings.Join(terms, " "),
+					Surface:  strings.Join(surfaces, " "),
+					Position: window[0].Position,
+				})
+			}
+		}
+		return out
+	})
+}
+
+func contiguous(window []Token) bool {
+	for j := 1; j < len(window); j++ {
+		if window[j].Position != window[j-1].Position+1 {
+			return false
+		}
+	}
+	return true
+}
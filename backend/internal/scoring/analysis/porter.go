@@ -0,0 +1,221 @@
+package analysis
+
+// Stem returns the Porter (1980) stem of a lowercase ASCII word.
+// See https://tartarus.org/martin/PorterStemmer/def.txt.
+func Stem(word string) string {
+	if len(word) <= 2 {
+		return word
+	}
+
+	w := []byte(word)
+	w = step1a(w)
+	w = step1b(w)
+	w = step1c(w)
+	w = step2(w)
+	w = step3(w)
+	w = step4(w)
+	w = step5(w)
+	return string(w)
+}
+
+type rule struct {
+	suffix      string
+	replacement string
+}
+
+func isConsonant(w []byte, i int) bool {
+	switch w[i] {
+	case 'a', 'e', 'i', 'o', 'u':
+		return false
+	case 'y':
+		return i == 0 || !isConsonant(w, i-1)
+	}
+	return true
+}
+
+// measure counts the VC sequences in w ([C](VC){m}[V]).
+func measure(w []byte) int {
+	m, i, n := 0, 0, len(w)
+	for i < n && isConsonant(w, i) {
+		i++
+	}
+	for i < n {
+		for i < n && !isConsonant(w, i) {
+			i++
+		}
+		if i >= n {
+			break
+		}
+		for i < n && isConsonant(w, i) {
+			i++
+		}
+		m++
+	}
+	return m
+}
+
+func hasVowel(w []byte) bool {
+	for i := range w {
+		if !isConsonant(w, i) {
+			return true
+		}
+	}
+	return false
+}
+
+func endsDoubleConsonant(w []byte) bool {
+	n := len(w)
+	return n >= 2 && w[n-1] == w[n-2] && isConsonant(w, n-1)
+}
+
+// endsCVC reports consonant-vowel-consonant where the last is not w, x or y.
+func endsCVC(w []byte) bool {
+	n := len(w)
+	if n < 3 || !isConsonant(w, n-3) || isConsonant(w, n-2) || !isConsonant(w, n-1) {
+		return false
+	}
+	switch w[n-1] {
+	case 'w', 'x', 'y':
+		return false
+	}
+	return true
+}
+
+func hasSuffix(w []byte, suffix string) bool {
+	return len(w) >= len(suffix) && string(w[len(w)-len(suffix):]) == suffix
+}
+
+func replaceSuffix(w []byte, suffix, replacement string) []byte {
+	return append(w[:len(w)-len(suffix)], replacement...)
+}
+
+// applyRules replaces the first matching suffix when the remaining stem has
+// a measure above minMeasure. Only the first match is considered.
+func applyRules(w []byte, rules []rule, minMeasure int) []byte {
+	for _, r := range rules {
+		if !hasSuffix(w, r.suffix) {
+			continue
+		}
+		if measure(w[:len(w)-len(r.suffix)]) > minMeasure {
+			return replaceSuffix(w, r.suffix, r.replacement)
+		}
+		return w
+	}
+	return w
+}
+
+func step1a(w []byte) []byte {
+	switch {
+	case hasSuffix(w, "sses"):
+		return replaceSuffix(w, "sses", "ss")
+	case hasSuffix(w, "ies"):
+		return replaceSuffix(w, "ies", "i")
+	case hasSuffix(w, "ss"):
+		return w
+	case hasSuffix(w, "s"):
+		return w[:len(w)-1]
+	}
+	return w
+}
+
+func step1b(w []byte) []byte {
+	if hasSuffix(w, "eed") {
+		if measure(w[:len(w)-3]) > 0 {
+			return w[:len(w)-1]
+		}
+		return w
+	}
+
+	var stem []byte
+	switch {
+	case hasSuffix(w, "ed") && hasVowel(w[:len(w)-2]):
+		stem = w[:len(w)-2]
+	case hasSuffix(w, "ing") && hasVowel(w[:len(w)-3]):
+		stem = w[:len(w)-3]
+	default:
+		return w
+	}
+
+	switch {
+	case hasSuffix(stem, "at"), hasSuffix(stem, "bl"), hasSuffix(stem, "iz"):
+		return append(stem, 'e')
+	case endsDoubleConsonant(stem):
+		switch stem[len(stem)-1] {
+		case 'l', 's', 'z':
+			return stem
+		}
+		return stem[:len(stem)-1]
+	case measure(stem) == 1 && endsCVC(stem):
+		return append(stem, 'e')
+	}
+	return stem
+}
+
+func step1c(w []byte) []byte {
+	if hasSuffix(w, "y") && hasVowel(w[:len(w)-1]) {
+		w[len(w)-1] = 'i'
+	}
+	return w
+}
+
+var step2Rules = []rule{
+	{"ational", "ate"}, {"tional", "tion"}, {"enci", "ence"}, {"anci", "ance"},
+	{"izer", "ize"}, {"abli", "able"}, {"alli", "al"}, {"entli", "ent"},
+	{"eli", "e"}, {"ousli", "ous"}, {"ization", "ize"}, {"ation", "ate"},
+	{"ator", "ate"}, {"alism", "al"}, {"iveness", "ive"}, {"fulness", "ful"},
+	{"ousness", "ous"}, {"aliti", "al"}, {"iviti", "ive"}, {"biliti", "ble"},
+}
+
+func step2(w []byte) []byte {
+	return applyRules(w, step2Rules, 0)
+}
+
+var step3Rules = []rule{
+	{"icate", "ic"}, {"ative", ""}, {"alize", "al"}, {"iciti", "ic"},
+	{"ical", "ic"}, {"ful", ""}, {"ness", ""},
+}
+
+func step3(w []byte) []byte {
+	return applyRules(w, step3Rules, 0)
+}
+
+var step4Suffixes = []string{
+	"al", "ance", "ence", "er", "ic", "able", "ible", "ant", "ement", "ment",
+	"ent", "ion", "ou", "ism", "ate", "iti", "ous", "ive", "ize",
+}
+
+func step4(w []byte) []byte {
+	// Pick the longest matching suffix, as the definition requires
+	match := ""
+	for _, s := range step4Suffixes {
+		if hasSuffix(w, s) && len(s) > len(match) {
+			match = s
+		}
+	}
+	if match == "" {
+		return w
+	}
+
+	stem := w[:len(w)-len(match)]
+	if measure(stem) <= 1 {
+		return w
+	}
+	if match == "ion" && !hasSuffix(stem, "s") && !hasSuffix(stem, "t") {
+		return w
+	}
+	return stem
+}
+
+func step5(w []byte) []byte {
+	if hasSuffix(w, "e") {
+		stem := w[:len(w)-1]
+		m := measure(stem)
+		if m > 1 || (m == 1 && !endsCVC(stem)) {
+			w = stem
+		}
+	}
+	if measure(w) > 1 && endsDoubleConsonant(w) && hasSuffix(w, "l") {
+		w = w[:len(w)-1]
+	}
+	return w
+}
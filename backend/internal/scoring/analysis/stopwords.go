@@ -0,0 +1,29 @@
+package analysis
+
+// EnglishStopwords is the stopword list used by Default. It extends the
+// classic function-word list with filler common in job postings.
+var EnglishStopwords = toSet(
+	"a", "about", "above", "after", "again", "against", "all", "also", "am", "an", "and", "any",
+	"are", "as", "at", "be", "because", "been", "before", "being", "below", "between", "both",
+	"but", "by", "can", "could", "did", "do", "does", "doing", "down", "during", "each", "e.g",
+	"etc", "few", "for", "from", "further", "had", "has", "have", "having", "he", "her", "here",
+	"hers", "herself", "him", "himself", "his", "how", "i", "i.e", "if", "in", "into", "is", "it",
+	"its", "itself", "just", "like", "may", "me", "more", "most", "must", "my", "myself", "no",
+	"nor", "not", "now", "of", "off", "on", "once", "only", "or", "other", "our", "ours",
+	"ourselves", "out", "over", "own", "same", "she", "should", "so", "some", "such", "than",
+	"that", "the", "their", "theirs", "them", "themselves", "then", "there", "these", "they",
+	"this", "those", "through", "to", "too", "under", "until", "up", "us", "very", "was", "we",
+	"well", "were", "what", "when", "where", "which", "while", "who", "whom", "why", "will",
+	"with", "within", "would", "you", "your", "yours", "yourself", "yourselves",
+	// job posting filler
+	"looking", "join", "ideal", "candidate", "role", "position", "team", "work", "working",
+	"strong", "ability", "able", "plus", "including", "years", "year",
+)
+
+func toSet(words ...string) map[string]struct{} {
+	set := make(map[string]struct{}, len(words))
+	for _, w := range words {
+		set[w] = struct{}{}
+	}
+	return set
+}
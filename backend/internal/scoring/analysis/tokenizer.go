@@ -0,0 +1,164 @@
+package analysis
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// connectors may appear inside a token ("node.js", "ci/cd", "c++", "c#").
+const connectors = "+#./-_'&"
+
+// protected tokens are kept whole even though they contain connectors that
+// would normally split them.
+var protected = map[string]struct{}{
+	"e-commerce": {},
+	"pl/sql":     {},
+	"t-sql":      {},
+	"a/b":        {},
+	"ci/cd":      {},
+	"r&d":        {},
+	"at&t":       {},
+}
+
+// StandardTokenizer is a Unicode-aware tokenizer that keeps common tech
+// tokens intact. Punctuation between words leaves a position gap so that
+// phrase extraction never spans a sentence or list boundary.
+type StandardTokenizer struct{}
+
+func NewStandardTokenizer() *StandardTokenizer {
+	return &StandardTokenizer{}
+}
+
+func (t *StandardTokenizer) Tokenize(text string) []Token {
+	var tokens []Token
+	pos := 0
+
+	emit := func(raw string) {
+		word, boundary := trimToken(raw)
+		if word == "" {
+			// A bare connector ("&", "/") still separates phrases
+			pos++
+			return
+		}
+		for _, part := range splitToken(word) {
+			tokens = append(tokens, Token{Term: part, Surface: part, Position: pos})
+			pos++
+		}
+		if boundary {
+			pos++
+		}
+	}
+
+	start := -1
+	for i, r := range text {
+		if isTokenRune(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			emit(text[start:i])
+			start = -1
+		}
+		if !unicode.IsSpace(r) {
+			pos++
+		}
+	}
+	if start >= 0 {
+		emit(text[start:])
+	}
+
+	return tokens
+}
+
+func isTokenRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r) || strings.ContainsRune(connectors, r)
+}
+
+// trimToken strips connectors that are not part of the word itself. It
+// reports whether sentence-ending punctuation was removed.
+func trimToken(raw string) (string, bool) {
+	boundary := false
+
+	// Trailing connectors, except "+" and "#" after a letter (c++, c#, f#)
+	for raw != "" {
+		r, size := utf8.DecodeLastRuneInString(raw)
+		if r == '+' || r == '#' {
+			rest := strings.TrimRight(raw, "+#")
+			last, _ := utf8.DecodeLastRuneInString(rest)
+			if rest != "" && unicode.IsLetter(last) {
+				break
+			}
+		}
+		if !strings.ContainsRune(connectors, r) {
+			break
+		}
+		if r == '.' {
+			boundary = true
+		}
+		raw = raw[:len(raw)-size]
+	}
+
+	// Leading connectors, except "." before letters (.net)
+	for raw != "" {
+		r, size := utf8.DecodeRuneInString(raw)
+		if !strings.ContainsRune(connectors, r) {
+			break
+		}
+		if r == '.' && len(raw) > size {
+			next, _ := utf8.DecodeRuneInString(raw[size:])
+			if unicode.IsLetter(next) {
+				break
+			}
+		}
+		raw = raw[size:]
+	}
+
+	// Possessives
+	for _, suffix := range []string{"'s", "’s"} {
+		if len(raw) > len(suffix) && strings.HasSuffix(strings.ToLower(raw), suffix) {
+			raw = raw[:len(raw)-len(suffix)]
+		}
+	}
+
+	return raw, boundary
+}
+
+// splitToken breaks compound words apart unless they look like a single
+// tech term: short slash pairs ("ui/ux", "tcp/ip") and protected tokens stay
+// whole, while "frontend/backend" and "cross-functional" are split.
+func splitToken(word string) []string {
+	if word == "" {
+		return nil
+	}
+	if _, ok := protected[strings.ToLower(word)]; ok {
+		return []string{word}
+	}
+
+	var out []string
+	for _, part := range strings.FieldsFunc(word, func(r rune) bool { return r == '-' || r == '_' || r == '\'' || r == '’' }) {
+		out = append(out, splitSlash(part)...)
+	}
+	return out
+}
+
+func splitSlash(word string) []string {
+	if !strings.ContainsAny(word, "/&") {
+		return []string{word}
+	}
+
+	parts := strings.FieldsFunc(word, func(r rune) bool { return r == '/' || r == '&' })
+	short := true
+	for _, p := range parts {
+		if utf8.RuneCountInString(p) > 3 {
+			short = false
+			break
+		}
+	}
+	if short && len(parts) > 1 {
+		return []string{word}
+	}
+	return parts
+}
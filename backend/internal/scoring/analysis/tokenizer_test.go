@@ -0,0 +1,70 @@
+package analysis
+
+import (
+	"reflect"
+	"testing"
+)
+
+func surfaces(tokens []Token) []string {
+	out := make([]string, len(tokens))
+	for i, t := range tokens {
+		out[i] = t.Surface
+	}
+	return out
+}
+
+func TestTokenizeTechTokens(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"C++ and C#", []string{"C++", "and", "C#"}},
+		{"Built with C#, F# and C++.", []string{"Built", "with", "C#", "F#", "and", "C++"}},
+		{".NET Core services", []string{".NET", "Core", "services"}},
+		{"APIs in Node.js.", []string{"APIs", "in", "Node.js"}},
+		{"CI/CD pipelines", []string{"CI/CD", "pipelines"}},
+		{"ci/cd and pl/sql", []string{"ci/cd", "and", "pl/sql"}},
+		{"UI/UX, TCP/IP", []string{"UI/UX", "TCP/IP"}},
+		{"frontend/backend", []string{"frontend", "backend"}},
+		{"cross-functional teams", []string{"cross", "functional", "teams"}},
+		{"e-commerce at AT&T", []string{"e-commerce", "at", "AT&T"}},
+		{"Google's SRE team", []string{"Google", "SRE", "team"}},
+	}
+	tokenizer := NewStandardTokenizer()
+	for _, tt := range tests {
+		if got := surfaces(tokenizer.Tokenize(tt.text)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Tokenize(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+// Punctuation leaves a position gap so phrases never span it.
+func TestTokenizePositions(t *testing.T) {
+	tokens := NewStandardTokenizer().Tokenize("Go, Kafka. Docker")
+	var got []int
+	for _, tok := range tokens {
+		got = append(got, tok.Position)
+	}
+	if want := []int{0, 2, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("positions = %v, want %v", got, want)
+	}
+}
+
+func TestDefaultAnalyzer(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		// Stopwords and posting filler go, the rest is stemmed; phrases
+		// only join words that were adjacent in the text
+		{"We are looking for engineers with strong Kubernetes skills", []string{"engin", "kubernet", "skill", "kubernet skill"}},
+		// Tech tokens are not stemmed and single-letter languages are kept
+		{"C++, Node.js and C", []string{"c++", "node.js", "c"}},
+	}
+	analyzer := Default()
+	for _, tt := range tests {
+		if got := analyzer.Terms(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Terms(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
@@ -1,8 +1,313 @@
 package bm25
 
-// Compute calculates BM25 signals for resume and job text matching.
-// TODO: Zakaria will implement BM25 here
-func Compute(resumeText, jobText string) (any, error) {
-	// TODO: Zakaria will implement BM25 here
-	return nil, nil
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"resume-tailor/internal/scoring/analysis"
+	"resume-tailor/internal/scoring/jobpost"
+	"resume-tailor/internal/scoring/sections"
+	"resume-tailor/internal/scoring/taxonomy"
+)
+
+const topDocumentsLimit = 5
+
+// Compute calculates BM25 signals for resume and job text matching
+// using DefaultParams, the default analyzer and the bundled skills
+// taxonomy. The job posting is the query and each resume chunk is a document.
+func Compute(resumeText, jobText string) (Signals, error) {
+	tax, err := taxonomy.Default()
+	if err != nil {
+		return Signals{}, err
+	}
+	return NewDefaultScorer(tax).Compute(resumeText, jobText)
+}
+
+// Scorer runs Okapi BM25 with a fixed set of parameters. The same analyzer
+// is applied to the resume and the posting so terms are comparable.
+type Scorer struct {
+	params   Params
+	analyzer *analysis.Analyzer
+	taxonomy *taxonomy.Taxonomy
+}
+
+// NewScorer creates a Scorer. The taxonomy is optional; when set, the
+// analyzer is expected to include its Filter so skills are normalized.
+func NewScorer(params Params, analyzer *analysis.Analyzer, tax *taxonomy.Taxonomy) *Scorer {
+	return &Scorer{params: params, analyzer: analyzer, taxonomy: tax}
+}
+
+// NewDefaultScorer wires DefaultParams with the default analyzer normalized
+// through the given taxonomy.
+func NewDefaultScorer(tax *taxonomy.Taxonomy) *Scorer {
+	return NewScorer(DefaultParams, analysis.Default(tax.Filter()), tax)
+}
+
+// Compute scores every resume chunk against the query terms extracted from
+// the job posting and summarizes which terms were found.
+func (s *Scorer) Compute(resumeText, jobText string) (Signals, error) {
+	if strings.TrimSpace(resumeText) == "" {
+		return Signals{}, ErrEmptyResume
+	}
+	if strings.TrimSpace(jobText) == "" {
+		return Signals{}, ErrEmptyJob
+	}
+
+	jobTokens, boilerplate := s.analyzeJob(jobText)
+	docs := s.buildDocuments(resumeText)
+	query := s.buildQuery(jobTokens)
+
+	signals := s.score(docs, query)
+	signals.BoilerplateLines = boilerplate
+	if s.taxonomy != nil {
+		signals.TaxonomyVersion = s.taxonomy.Version
+		signals.Skills = s.matchSkills(jobTokens, docs)
+	}
+	return signals, nil
+}
+
+// buildDocuments detects resume sections, chunks each section and analyzes
+// every chunk. Each document remembers the section it came from.
+func (s *Scorer) buildDocuments(resumeText string) []Document {
+	var docs []Document
+	for _, sec := range sections.Detect(resumeText) {
+		for _, c := range chunk(sec.Text) {
+			tokens := s.analyzer.Analyze(c)
+			if len(tokens) == 0 {
+				continue
+			}
+			terms := make([]string, len(tokens))
+			for i, t := range tokens {
+				terms[i] = t.Term
+			}
+			docs = append(docs, Document{
+				ID:      len(docs),
+				Section: sec.Kind,
+				Text:    c,
+				Terms:   terms,
+				tokens:  tokens,
+			})
+		}
+	}
+	return docs
+}
+
+// jobToken is an analyzed posting token tagged with its requirement group.
+type jobToken struct {
+	analysis.Token
+	group jobpost.Group
+}
+
+// analyzeJob splits the posting into requirement groups and analyzes every
+// line that carries weight. It also reports how many boilerplate lines
+// were excluded. Without group weights the posting is analyzed as a whole.
+func (s *Scorer) analyzeJob(jobText string) ([]jobToken, int) {
+	if s.params.GroupWeights == nil {
+		var out []jobToken
+		for _, t := range s.analyzer.Analyze(jobText) {
+			out = append(out, jobToken{Token: t, group: jobpost.GroupGeneral})
+		}
+		return out, 0
+	}
+
+	posting := jobpost.Parse(jobText)
+	var out []jobToken
+	for _, line := range posting.Lines {
+		if s.groupWeight(line.Group) <= 0 {
+			continue
+		}
+		for _, t := range s.analyzer.Analyze(line.Text) {
+			out = append(out, jobToken{Token: t, group: line.Group})
+		}
+	}
+	return out, posting.Count(jobpost.GroupBoilerplate)
+}
+
+func (s *Scorer) groupWeight(g jobpost.Group) float64 {
+	if s.params.GroupWeights == nil {
+		return 1
+	}
+	return s.params.GroupWeights[g]
+}
+
+// queryTerm is a deduplicated posting term with its first surface form.
+// Its weight sums the group weight of every occurrence and its group is the
+// heaviest group it appeared in.
+type queryTerm struct {
+	term    string
+	surface string
+	skill   bool
+	group   jobpost.Group
+	weight  float64
+	freq    int
+}
+
+// buildQuery dedupes query terms while keeping first-occurrence order so
+// the output is reproducible for the same posting. Skills are displayed
+// under their canonical name rather than the alias used in the posting.
+func (s *Scorer) buildQuery(tokens []jobToken) []queryTerm {
+	index := make(map[string]int, len(tokens))
+	query := make([]queryTerm, 0, len(tokens))
+	for _, t := range tokens {
+		w := s.groupWeight(t.group)
+		if i, ok := index[t.Term]; ok {
+			query[i].freq++
+			query[i].weight += w
+			if w > s.groupWeight(query[i].group) {
+				query[i].group = t.group
+			}
+			continue
+		}
+		index[t.Term] = len(query)
+		query = append(query, queryTerm{
+			term:    t.Term,
+			surface: s.display(t.Token),
+			skill:   t.Keyword,
+			group:   t.group,
+			weight:  w,
+			freq:    1,
+		})
+	}
+	return query
+}
+
+func (s *Scorer) display(t analysis.Token) string {
+	if t.Keyword && s.taxonomy != nil {
+		if skill, ok := s.taxonomy.Lookup(t.Term); ok {
+			return skill.Name
+		}
+	}
+	return strings.ToLower(t.Surface)
+}
+
+func (s *Scorer) score(docs []Document, query []queryTerm) Signals {
+	n := len(docs)
+
+	// Term frequencies per document and document frequency per term
+	tfs := make([]map[string]int, n)
+	df := make(map[string]int)
+	for i, d := range docs {
+		tf := make(map[string]int, len(d.Terms))
+		for _, t := range d.Terms {
+			tf[t]++
+		}
+		for t := range tf {
+			df[t]++
+		}
+		tfs[i] = tf
+	}
+	fields := s.newFieldStats(docs)
+
+	signals := Signals{
+		Terms:           make([]TermScore, 0, len(query)),
+		MatchedTerms:    []string{},
+		MissingTerms:    []string{},
+		MatchedPhrases:  []string{},
+		MissingRequired: []string{},
+		IDF:             make(map[string]float64, len(query)),
+		DocumentCount:   n,
+		AvgDocLength:    fields.avgLen,
+	}
+
+	docScores := make([]float64, n)
+	unigrams := 0
+	totalWeight, matchedWeight := 0.0, 0.0
+	for _, q := range query {
+		term := q.term
+		idf := idf(n, df[term])
+		ts := TermScore{
+			Term:         term,
+			Surface:      q.surface,
+			Phrase:       strings.Contains(term, " "),
+			Skill:        q.skill,
+			Group:        q.group,
+			Weight:       q.weight,
+			QueryFreq:    q.freq,
+			DocFreq:      df[term],
+			IDF:          idf,
+			BestDocument: -1,
+			Sections:     []sections.Kind{},
+		}
+
+		for i, d := range docs {
+			f := tfs[i][term]
+			if f == 0 {
+				continue
+			}
+			w := idf * s.saturate(fields.pseudoFreq(d, float64(f)))
+			docScores[i] += w * ts.Weight
+			if w > ts.Score {
+				ts.Score = w
+				ts.BestDocument = d.ID
+			}
+			ts.Sections = appendSection(ts.Sections, d.Section)
+		}
+
+		ts.Matched = ts.DocFreq > 0
+		switch {
+		case ts.Phrase:
+			// Posting phrases are only reported when found; most word pairs
+			// in a posting are not meaningful on their own.
+			if ts.Matched {
+				signals.MatchedPhrases = append(signals.MatchedPhrases, ts.Surface)
+			}
+		case ts.Matched:
+			unigrams++
+			totalWeight += ts.Weight
+			matchedWeight += ts.Weight
+			signals.MatchedTerms = append(signals.MatchedTerms, ts.Surface)
+		default:
+			unigrams++
+			totalWeight += ts.Weight
+			signals.MissingTerms = append(signals.MissingTerms, ts.Surface)
+			if ts.Group == jobpost.GroupRequired {
+				signals.MissingRequired = append(signals.MissingRequired, ts.Surface)
+			}
+		}
+		signals.IDF[term] = idf
+		signals.Terms = append(signals.Terms, ts)
+	}
+
+	if unigrams > 0 {
+		signals.Coverage = float64(len(signals.MatchedTerms)) / float64(unigrams)
+	}
+	if totalWeight > 0 {
+		signals.WeightedCoverage = matchedWeight / totalWeight
+	}
+	signals.TopDocuments = topDocuments(docs, docScores, topDocumentsLimit)
+	signals.Sections = s.sectionCoverage(docs, signals.Terms, unigrams)
+
+	return signals
+}
+
+// saturate is the BM25 term-frequency saturation applied to an already
+// length-normalized (and, for BM25F, field-weighted) frequency.
+func (s *Scorer) saturate(tf float64) float64 {
+	return tf * (s.params.K1 + 1) / (tf + s.params.K1)
+}
+
+// idf uses the non-negative BM25 variant so terms present in every chunk
+// still contribute a small positive weight.
+func idf(n, df int) float64 {
+	return math.Log(1 + (float64(n)-float64(df)+0.5)/(float64(df)+0.5))
+}
+
+func topDocuments(docs []Document, scores []float64, limit int) []DocumentScore {
+	out := make([]DocumentScore, 0, len(docs))
+	for i, d := range docs {
+		if scores[i] <= 0 {
+			continue
+		}
+		out = append(out, DocumentScore{ID: d.ID, Section: d.Section, Text: d.Text, Score: scores[i]})
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].Score > out[j].Score
+	})
+
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out
 }
@@ -0,0 +1,146 @@
+package bm25
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"resume-tailor/internal/scoring/analysis"
+)
+
+// tinyResume has three bullet documents: "go kafka" (2 terms),
+// "go postgres go" (3 terms) and "python" (1 term); avgdl is 2.
+const tinyResume = "- go kafka\n- go postgres go\n- python"
+
+// plainScorer is classic BM25 without field or group weights over an
+// analyzer that only lowercases, so the expected values can be worked out
+// by hand.
+func plainScorer(params Params) *Scorer {
+	return NewScorer(params, analysis.New(analysis.NewStandardTokenizer(), analysis.LowercaseFilter()), nil)
+}
+
+func approx(t *testing.T, name string, got, want float64) {
+	t.Helper()
+	if math.Abs(got-want) > 1e-9 {
+		t.Errorf("%s = %v, want %v", name, got, want)
+	}
+}
+
+func TestComputeScores(t *testing.T) {
+	const k1, b = 1.2, 0.75
+	signals, err := plainScorer(Params{K1: k1, B: b}).Compute(tinyResume, "go kafka rust")
+	if err != nil {
+		t.Fatalf("Compute: %v", err)
+	}
+
+	if signals.DocumentCount != 3 || signals.AvgDocLength != 2 {
+		t.Fatalf("N=%d avgdl=%v, want 3 and 2", signals.DocumentCount, signals.AvgDocLength)
+	}
+
+	// idf = ln(1 + (N - df + 0.5) / (df + 0.5))
+	approx(t, "idf(go)", signals.IDF["go"], math.Log(1+1.5/2.5))
+	approx(t, "idf(kafka)", signals.IDF["kafka"], math.Log(1+2.5/1.5))
+	approx(t, "idf(rust)", signals.IDF["rust"], math.Log(1+3.5/0.5))
+
+	// score = idf * f(k1+1) / (f + k1), with f = tf / (1 - b + b*dl/avgdl)
+	saturate := func(tf, dl float64) float64 {
+		f := tf / (1 - b + b*dl/2)
+		return f * (k1 + 1) / (f + k1)
+	}
+	byTerm := make(map[string]TermScore)
+	for _, ts := range signals.Terms {
+		byTerm[ts.Term] = ts
+	}
+
+	// "go" scores best in document 1, where it appears twice
+	goScore := byTerm["go"]
+	approx(t, "score(go)", goScore.Score, signals.IDF["go"]*saturate(2, 3))
+	if goScore.BestDocument != 1 || goScore.DocFreq != 2 {
+		t.Errorf("go: best document %d df %d, want 1 and 2", goScore.BestDocument, goScore.DocFreq)
+	}
+	// In a document of average length a single occurrence saturates to 1
+	approx(t, "score(kafka)", byTerm["kafka"].Score, signals.IDF["kafka"])
+	if rust := byTerm["rust"]; rust.Score != 0 || rust.Matched || rust.BestDocument != -1 {
+		t.Errorf("rust: %+v, want unmatched with score 0", rust)
+	}
+
+	if len(signals.TopDocuments) != 2 || signals.TopDocuments[0].ID != 0 {
+		t.Fatalf("top documents %+v, want documents 0 then 1", signals.TopDocuments)
+	}
+	approx(t, "score(doc 0)", signals.TopDocuments[0].Score, signals.IDF["go"]*saturate(1, 2)+signals.IDF["kafka"]*saturate(1, 2))
+
+	if !reflect.DeepEqual(signals.MatchedTerms, []string{"go", "kafka"}) || !reflect.DeepEqual(signals.MissingTerms, []string{"rust"}) {
+		t.Errorf("matched %v missing %v, want [go kafka] and [rust]", signals.MatchedTerms, signals.MissingTerms)
+	}
+	approx(t, "coverage", signals.Coverage, 2.0/3)
+	approx(t, "weighted coverage", signals.WeightedCoverage, 2.0/3)
+}
+
+func TestComputeWeightedCoverage(t *testing.T) {
+	job := "Requirements:\n- go\n- rust\nNice to have:\n- kafka"
+	signals, err := plainScorer(Params{K1: 1.2, B: 0.75, GroupWeights: DefaultGroupWeights}).Compute(tinyResume, job)
+	if err != nil {
+		t.Fatalf("Compute: %v", err)
+	}
+
+	if !reflect.DeepEqual(signals.MatchedTerms, []string{"go", "kafka"}) || !reflect.DeepEqual(signals.MissingTerms, []string{"rust"}) {
+		t.Errorf("matched %v missing %v, want [go kafka] and [rust]", signals.MatchedTerms, signals.MissingTerms)
+	}
+	if !reflect.DeepEqual(signals.MissingRequired, []string{"rust"}) {
+		t.Errorf("missing required %v, want [rust]", signals.MissingRequired)
+	}
+	approx(t, "coverage", signals.Coverage, 2.0/3)
+
+	// go and rust are required (2.0), kafka preferred (0.5): (2 + 0.5) / 4.5
+	approx(t, "weighted coverage", signals.WeightedCoverage, 2.5/4.5)
+}
+
+func TestComputeSkills(t *testing.T) {
+	resume := "Experience\n- Built payment services in Golang on PostgreSQL"
+	job := "Requirements:\n- Go and Kubernetes\n- Postgres"
+	signals, err := Compute(resume, job)
+	if err != nil {
+		t.Fatalf("Compute: %v", err)
+	}
+
+	skills := make(map[string]SkillMatch)
+	for _, s := range signals.Skills {
+		skills[s.ID] = s
+	}
+	tests := []struct {
+		id      string
+		matched bool
+		aliases []string
+	}{
+		{id: "go", matched: true, aliases: []string{"golang"}},
+		{id: "postgresql", matched: true, aliases: []string{"postgresql"}},
+		{id: "kubernetes", matched: false, aliases: []string{}},
+	}
+	for _, tt := range tests {
+		s, ok := skills[tt.id]
+		if !ok {
+			t.Errorf("skill %s not requested, got %v", tt.id, signals.Skills)
+			continue
+		}
+		if s.Matched != tt.matched || !reflect.DeepEqual(s.ResumeAliases, tt.aliases) {
+			t.Errorf("skill %s: matched %v aliases %v, want %v %v", tt.id, s.Matched, s.ResumeAliases, tt.matched, tt.aliases)
+		}
+	}
+}
+
+func TestComputeEmptyInput(t *testing.T) {
+	if _, err := Compute(" ", "go"); err != ErrEmptyResume {
+		t.Errorf("empty resume: got %v, want ErrEmptyResume", err)
+	}
+	if _, err := Compute("go", "\n"); err != ErrEmptyJob {
+		t.Errorf("empty job: got %v, want ErrEmptyJob", err)
+	}
+}
+
+func TestChunk(t *testing.T) {
+	text := "Senior Engineer\nAcme Corp\n- Built APIs\n* Led a team\n\nSecond block"
+	want := []string{"Senior Engineer Acme Corp", "Built APIs", "Led a team", "Second block"}
+	if got := chunk(text); !reflect.DeepEqual(got, want) {
+		t.Errorf("chunk = %q, want %q", got, want)
+	}
+}
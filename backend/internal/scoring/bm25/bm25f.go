@@ -0,0 +1,111 @@
+package bm25
+
+import "resume-tailor/internal/scoring/sections"
+
+// fieldStats holds the length statistics needed for BM25F normalization.
+// Every resume chunk belongs to exactly one field (its section), so BM25F
+// reduces to per-section length normalization and a per-section boost.
+type fieldStats struct {
+	b       float64
+	weights FieldWeights
+	avgLen  float64
+	byField map[sections.Kind]float64
+}
+
+func (s *Scorer) newFieldStats(docs []Document) fieldStats {
+	total := 0
+	lens := make(map[sections.Kind]int)
+	counts := make(map[sections.Kind]int)
+	for _, d := range docs {
+		total += len(d.Terms)
+		lens[d.Section] += len(d.Terms)
+		counts[d.Section]++
+	}
+
+	fs := fieldStats{
+		b:       s.params.B,
+		weights: s.params.FieldWeights,
+		byField: make(map[sections.Kind]float64, len(lens)),
+	}
+	if len(docs) > 0 {
+		fs.avgLen = float64(total) / float64(len(docs))
+	}
+	for kind, l := range lens {
+		fs.byField[kind] = float64(l) / float64(counts[kind])
+	}
+	return fs
+}
+
+// weight returns the boost for a section; plain BM25 weighs everything 1.
+func (fs fieldStats) weight(kind sections.Kind) float64 {
+	if fs.weights == nil {
+		return 1
+	}
+	if w, ok := fs.weights[kind]; ok {
+		return w
+	}
+	return 1
+}
+
+// pseudoFreq is the length-normalized, field-weighted term frequency fed to
+// the saturation function. Without field weights this is classic BM25.
+func (fs fieldStats) pseudoFreq(d Document, tf float64) float64 {
+	avg := fs.avgLen
+	if fs.weights != nil {
+		avg = fs.byField[d.Section]
+	}
+
+	norm := 1.0
+	if avg > 0 {
+		norm = 1 - fs.b + fs.b*float64(len(d.Terms))/avg
+	}
+	return fs.weight(d.Section) * tf / norm
+}
+
+// sectionCoverage reports, for every section present in the resume, which
+// single-word posting terms it contains.
+func (s *Scorer) sectionCoverage(docs []Document, terms []TermScore, unigrams int) []SectionCoverage {
+	fs := fieldStats{weights: s.params.FieldWeights}
+
+	var out []SectionCoverage
+	index := make(map[sections.Kind]int)
+	for _, d := range docs {
+		if i, ok := index[d.Section]; ok {
+			out[i].DocumentCount++
+			continue
+		}
+		index[d.Section] = len(out)
+		out = append(out, SectionCoverage{
+			Section:       d.Section,
+			Weight:        fs.weight(d.Section),
+			DocumentCount: 1,
+			MatchedTerms:  []string{},
+		})
+	}
+
+	for _, t := range terms {
+		if t.Phrase {
+			continue
+		}
+		for _, kind := range t.Sections {
+			i := index[kind]
+			out[i].MatchedTerms = append(out[i].MatchedTerms, t.Surface)
+		}
+	}
+
+	if unigrams > 0 {
+		for i := range out {
+			out[i].Coverage = float64(len(out[i].MatchedTerms)) / float64(unigrams)
+		}
+	}
+	return out
+}
+
+func appendSection(list []sections.Kind, kind sections.Kind) []sections.Kind {
+	for _, k := range list {
+		if k == kind {
+			return list
+		}
+	}
+	return append(list, kind)
+}
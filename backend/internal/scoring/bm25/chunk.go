@@ -0,0 +1,45 @@
+package bm25
+
+import "strings"
+
+// chunk splits resume text into documents: blank lines separate blocks,
+// and every bullet line inside a block becomes its own document.
+func chunk(text string) []string {
+	var chunks []string
+	var current []string
+
+	flush := func() {
+		if len(current) > 0 {
+			chunks = append(chunks, strings.Join(current, " "))
+			current = nil
+		}
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			flush()
+			continue
+		}
+		if bullet, ok := trimBullet(line); ok {
+			flush()
+			if bullet != "" {
+				chunks = append(chunks, bullet)
+			}
+			continue
+		}
+		current = append(current, line)
+	}
+	flush()
+
+	return chunks
+}
+
+func trimBullet(line string) (string, bool) {
+	for _, marker := range []string{"-", "*", "•", "·", "–", "▪"} {
+		if strings.HasPrefix(line, marker) {
+			return strings.TrimSpace(strings.TrimPrefix(line, marker)), true
+		}
+	}
+	return line, false
+}
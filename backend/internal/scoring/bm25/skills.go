@@ -0,0 +1,80 @@
+package bm25
+
+import (
+	"strings"
+
+	"resume-tailor/internal/scoring/sections"
+)
+
+// matchSkills lists every canonical skill in the posting, in order of first
+// mention, with the aliases that produced it on both sides.
+func (s *Scorer) matchSkills(jobTokens []jobToken, docs []Document) []SkillMatch {
+	resumeAliases, resumeSections := skillsInDocuments(docs)
+
+	var out []SkillMatch
+	seen := make(map[string]int)
+	for _, t := range jobTokens {
+		if !t.Keyword {
+			continue
+		}
+		alias := strings.ToLower(t.Surface)
+		if i, ok := seen[t.Term]; ok {
+			out[i].JobAliases = appendUnique(out[i].JobAliases, alias)
+			if s.groupWeight(t.group) > s.groupWeight(out[i].Group) {
+				out[i].Group = t.group
+			}
+			continue
+		}
+
+		skill, ok := s.taxonomy.Lookup(t.Term)
+		if !ok {
+			continue
+		}
+		found := resumeAliases[t.Term]
+		if found == nil {
+			found = []string{}
+		}
+		where := resumeSections[t.Term]
+		if where == nil {
+			where = []sections.Kind{}
+		}
+		seen[t.Term] = len(out)
+		out = append(out, SkillMatch{
+			ID:             skill.ID,
+			Name:           skill.Name,
+			Category:       skill.Category,
+			Group:          t.group,
+			JobAliases:     []string{alias},
+			ResumeAliases:  found,
+			Matched:        len(found) > 0,
+			ResumeSections: where,
+		})
+	}
+	return out
+}
+
+// skillsInDocuments collects, per skill ID, the aliases used in the resume
+// and the sections they appear in.
+func skillsInDocuments(docs []Document) (map[string][]string, map[string][]sections.Kind) {
+	aliases := make(map[string][]string)
+	where := make(map[string][]sections.Kind)
+	for _, d := range docs {
+		for _, t := range d.tokens {
+			if !t.Keyword {
+				continue
+			}
+			aliases[t.Term] = appendUnique(aliases[t.Term], strings.ToLower(t.Surface))
+			where[t.Term] = appendSection(where[t.Term], d.Section)
+		}
+	}
+	return aliases, where
+}
+
+func appendUnique(list []string, v string) []string {
+	for _, existing := range list {
+		if existing == v {
+			return list
+		}
+	}
+	return append(list, v)
+}
@@ -0,0 +1,149 @@
+package bm25
+
+import (
+	"errors"
+
+	"resume-tailor/internal/scoring/analysis"
+	"resume-tailor/internal/scoring/jobpost"
+	"resume-tailor/internal/scoring/sections"
+)
+
+// Params holds the BM25 tuning constants. When FieldWeights is set the
+// scorer runs BM25F, treating each resume section as a field. When
+// GroupWeights is set the posting is split into requirement groups and
+// query terms are weighted by group; groups weighted 0 are excluded.
+type Params struct {
+	K1           float64
+	B            float64
+	FieldWeights FieldWeights
+	GroupWeights GroupWeights
+}
+
+// FieldWeights boosts or dampens matches by the section they occur in.
+type FieldWeights map[sections.Kind]float64
+
+// DefaultFieldWeights favour evidence in Experience and Projects over the
+// same keyword listed in Skills or buried in Interests.
+var DefaultFieldWeights = FieldWeights{
+	sections.KindExperience:     1.5,
+	sections.KindProjects:       1.2,
+	sections.KindSummary:        1.0,
+	sections.KindSkills:         0.8,
+	sections.KindCertifications: 0.8,
+	sections.KindEducation:      0.6,
+	sections.KindHeader:         0.3,
+	sections.KindOther:          0.3,
+}
+
+// GroupWeights scales query terms by the posting group they come from.
+type GroupWeights map[jobpost.Group]float64
+
+// DefaultGroupWeights favour must-haves and drop benefits/EEO boilerplate.
+var DefaultGroupWeights = GroupWeights{
+	jobpost.GroupRequired:         2.0,
+	jobpost.GroupResponsibilities: 1.0,
+	jobpost.GroupGeneral:          1.0,
+	jobpost.GroupPreferred:        0.5,
+	jobpost.GroupBoilerplate:      0,
+}
+
+// DefaultParams are the textbook Okapi BM25 constants with BM25F section
+// weighting and requirement-group query weighting.
+var DefaultParams = Params{
+	K1:           1.2,
+	B:            0.75,
+	FieldWeights: DefaultFieldWeights,
+	GroupWeights: DefaultGroupWeights,
+}
+
+// Document is a scoreable chunk of the resume (a paragraph or a bullet).
+type Document struct {
+	ID      int           `json:"id"`
+	Section sections.Kind `json:"section"`
+	Text    string        `json:"text"`
+	Terms   []string      `json:"-"`
+
+	tokens []analysis.Token
+}
+
+// TermScore explains how a single query term from the job posting scored.
+type TermScore struct {
+	Term         string        `json:"term"`
+	Surface      string        `json:"surface"`
+	Phrase       bool          `json:"phrase"`
+	Skill        bool          `json:"skill"`
+	Group        jobpost.Group `json:"group"`
+	Weight       float64       `json:"weight"`
+	QueryFreq    int           `json:"query_freq"`
+	DocFreq      int           `json:"doc_freq"`
+	IDF          float64       `json:"idf"`
+	Score        float64       `json:"score"`
+	Matched      bool          `json:"matched"`
+	BestDocument int           `json:"best_document"`
+
+	// Sections lists every resume section the term was found in
+	Sections []sections.Kind `json:"sections"`
+}
+
+// DocumentScore is the total BM25 score of one resume chunk against the posting.
+type DocumentScore struct {
+	ID      int           `json:"id"`
+	Section sections.Kind `json:"section"`
+	Text    string        `json:"text"`
+	Score   float64       `json:"score"`
+}
+
+// SectionCoverage breaks posting-term coverage down by resume section.
+type SectionCoverage struct {
+	Section       sections.Kind `json:"section"`
+	Weight        float64       `json:"weight"`
+	DocumentCount int           `json:"document_count"`
+	MatchedTerms  []string      `json:"matched_terms"`
+	Coverage      float64       `json:"coverage"`
+}
+
+// Signals is the explainable output of a BM25 run. MatchedTerms and
+// MissingTerms hold single words; Coverage is computed over those only.
+type Signals struct {
+	Terms          []TermScore        `json:"terms"`
+	MatchedTerms   []string           `json:"matched_terms"`
+	MissingTerms   []string           `json:"missing_terms"`
+	MatchedPhrases []string           `json:"matched_phrases"`
+	IDF            map[string]float64 `json:"idf"`
+	Coverage       float64            `json:"coverage"`
+
+	// WeightedCoverage is Coverage with each term scaled by its group weight;
+	// MissingRequired lists missing terms from must-have requirements.
+	WeightedCoverage float64  `json:"weighted_coverage"`
+	MissingRequired  []string `json:"missing_required"`
+	BoilerplateLines int      `json:"boilerplate_lines"`
+
+	DocumentCount int               `json:"document_count"`
+	AvgDocLength  float64           `json:"avg_doc_length"`
+	TopDocuments  []DocumentScore   `json:"top_documents"`
+	Sections      []SectionCoverage `json:"sections"`
+
+	TaxonomyVersion string       `json:"taxonomy_version,omitempty"`
+	Skills          []SkillMatch `json:"skills,omitempty"`
+}
+
+// SkillMatch reports a canonical skill requested by the posting and which
+// aliases of it were found on each side.
+type SkillMatch struct {
+	ID            string        `json:"id"`
+	Name          string        `json:"name"`
+	Category      string        `json:"category"`
+	Group         jobpost.Group `json:"group"`
+	JobAliases    []string      `json:"job_aliases"`
+	ResumeAliases []string      `json:"resume_aliases"`
+	Matched       bool          `json:"matched"`
+
+	// ResumeSections lists where the skill appears, so a skill that is only
+	// listed under Skills and never evidenced in Experience can be flagged
+	ResumeSections []sections.Kind `json:"resume_sections"`
+}
+
+var (
+	ErrEmptyResume = errors.New("bm25: resume text is empty")
+	ErrEmptyJob    = errors.New("bm25: job text is empty")
+)
@@ -0,0 +1,144 @@
+package experience
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// month is a calendar month counted from year 0 (year*12 + month-1), which
+// makes interval arithmetic trivial.
+type month int
+
+func newMonth(year int, m time.Month) month {
+	return month(year*12 + int(m) - 1)
+}
+
+func (m month) String() string {
+	return strconv.Itoa(int(m)/12) + "-" + pad2(int(m)%12+1)
+}
+
+func pad2(n int) string {
+	if n < 10 {
+		return "0" + strconv.Itoa(n)
+	}
+	return strconv.Itoa(n)
+}
+
+// interval is an inclusive range of months.
+type interval struct {
+	start, end month
+}
+
+func (iv interval) months() int {
+	return int(iv.end-iv.start) + 1
+}
+
+const datePattern = `(?:(jan(?:uary)?|feb(?:ruary)?|mar(?:ch)?|apr(?:il)?|may|june?|july?|aug(?:ust)?|sep(?:t(?:ember)?)?|oct(?:ober)?|nov(?:ember)?|dec(?:ember)?)\.?\s+|(\d{1,2})\s*/\s*)?((?:19|20)\d{2})`
+
+// rangePattern matches "Jan 2019 – Present", "03/2018 - 06/2020",
+// "2017 to 2019"... Groups: 1-3 start (month name, numeric month, year),
+// 4-6 end, 7 open-ended keyword.
+var rangePattern = regexp.MustCompile(`(?i)\b` + datePattern + `\s*(?:-|–|—|to|until|till)\s*(?:` + datePattern + `|(present|current|now|today|date))\b`)
+
+var monthNames = map[string]time.Month{
+	"jan": time.January, "feb": time.February, "mar": time.March, "apr": time.April,
+	"may": time.May, "jun": time.June, "jul": time.July, "aug": time.August,
+	"sep": time.September, "oct": time.October, "nov": time.November, "dec": time.December,
+}
+
+// dateRange is a parsed date range and where it was found in the line.
+type dateRange struct {
+	interval
+	current bool
+	loc     []int
+}
+
+// findRange returns the first date range in a line. Ranges that end before
+// they start are ignored.
+func findRange(line string, now time.Time) (dateRange, bool) {
+	m := rangePattern.FindStringSubmatchIndex(line)
+	if m == nil {
+		return dateRange{}, false
+	}
+	group := func(i int) string {
+		if m[2*i] < 0 {
+			return ""
+		}
+		return line[m[2*i]:m[2*i+1]]
+	}
+
+	start, ok := parseDate(group(1), group(2), group(3), time.January)
+	if !ok {
+		return dateRange{}, false
+	}
+
+	var end month
+	current := group(7) != ""
+	if current {
+		end = newMonth(now.Year(), now.Month())
+	} else {
+		end, ok = parseDate(group(4), group(5), group(6), time.December)
+		if !ok {
+			return dateRange{}, false
+		}
+	}
+	if end < start {
+		return dateRange{}, false
+	}
+
+	return dateRange{interval: interval{start: start, end: end}, current: current, loc: m[:2]}, true
+}
+
+// parseDate builds a month from the regex groups. A bare year defaults to
+// the given month (January for starts, December for ends).
+func parseDate(name, numeric, year string, fallback time.Month) (month, bool) {
+	y, err := strconv.Atoi(year)
+	if err != nil {
+		return 0, false
+	}
+
+	mo := fallback
+	switch {
+	case name != "":
+		mo = monthNames[strings.ToLower(name)[:3]]
+	case numeric != "":
+		n, err := strconv.Atoi(numeric)
+		if err != nil || n < 1 || n > 12 {
+			return 0, false
+		}
+		mo = time.Month(n)
+	}
+	return newMonth(y, mo), true
+}
+
+// mergedMonths merges overlapping or adjacent intervals and returns the
+// total number of distinct months covered.
+func mergedMonths(ivs []interval) int {
+	if len(ivs) == 0 {
+		return 0
+	}
+
+	sorted := make([]interval, len(ivs))
+	copy(sorted, ivs)
+	for i := 1; i < len(sorted); i++ {
+		for j := i; j > 0 && sorted[j].start < sorted[j-1].start; j-- {
+			sorted[j], sorted[j-1] = sorted[j-1], sorted[j]
+		}
+	}
+
+	total := 0
+	cur := sorted[0]
+	for _, iv := range sorted[1:] {
+		if iv.start <= cur.end+1 {
+			if iv.end > cur.end {
+				cur.end = iv.end
+			}
+			continue
+		}
+		total += cur.months()
+		cur = iv
+	}
+	return total + cur.months()
+}
@@ -0,0 +1,354 @@
+package experience
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"resume-tailor/internal/scoring/analysis"
+	"resume-tailor/internal/scoring/jobpost"
+	"resume-tailor/internal/scoring/sections"
+	"resume-tailor/internal/scoring/taxonomy"
+)
+
+// Extractor finds employment history in a resume and experience
+// requirements in a posting, and compares them.
+type Extractor struct {
+	taxonomy *taxonomy.Taxonomy
+	analyzer *analysis.Analyzer
+}
+
+// NewExtractor creates an Extractor. Skills are recognized through the
+// taxonomy only; no stemming or stopword removal is needed here.
+func NewExtractor(tax *taxonomy.Taxonomy) *Extractor {
+	return &Extractor{
+		taxonomy: tax,
+		analyzer: analysis.New(analysis.NewStandardTokenizer(), analysis.LowercaseFilter(), tax.Filter()),
+	}
+}
+
+// Extract computes experience signals. now resolves open-ended ranges
+// ("Present") and is passed in so results are reproducible.
+func (e *Extractor) Extract(resumeText, jobText string, now time.Time) Signals {
+	positions, intervals := e.positions(resumeText, now)
+
+	totalMonths := mergedMonths(intervals)
+
+	skills, skillMonths := e.skillExperience(positions, intervals)
+
+	signals := Signals{
+		Positions:    positions,
+		TotalMonths:  totalMonths,
+		TotalYears:   years(totalMonths),
+		Skills:       skills,
+		Requirements: []Requirement{},
+		Gaps:         []Gap{},
+	}
+
+	for _, req := range e.requirements(jobText) {
+		months := totalMonths
+		if req.Skill != "" {
+			months = skillMonths[req.Skill]
+		}
+		req.EvidencedYears = years(months)
+		req.Met = req.EvidencedYears >= req.MinYears
+		signals.Requirements = append(signals.Requirements, req)
+
+		if !req.Met {
+			signals.Gaps = append(signals.Gaps, Gap{
+				Skill:          req.Skill,
+				Name:           req.Name,
+				RequiredYears:  req.MinYears,
+				EvidencedYears: req.EvidencedYears,
+				Group:          req.Group,
+				Message:        fmt.Sprintf("posting asks %sy %s, resume evidences ~%sy", formatYears(req.MinYears), req.Name, formatYears(req.EvidencedYears)),
+			})
+		}
+	}
+
+	signals.Seniority = seniority(jobText, positions, signals.TotalYears)
+	return signals
+}
+
+// positions collects dated entries from the Experience section (or the
+// whole resume minus Education when no Experience heading is found).
+func (e *Extractor) positions(resumeText string, now time.Time) ([]Position, []interval) {
+	secs := sections.Detect(resumeText)
+	hasExperience := false
+	for _, s := range secs {
+		if s.Kind == sections.KindExperience {
+			hasExperience = true
+			break
+		}
+	}
+
+	var lines []string
+	for _, s := range secs {
+		if hasExperience && s.Kind != sections.KindExperience {
+			continue
+		}
+		if !hasExperience && s.Kind == sections.KindEducation {
+			continue
+		}
+		lines = append(lines, strings.Split(s.Text, "\n")...)
+	}
+
+	type entry struct {
+		dateRange
+		title string
+		text  []string
+	}
+	var entries []entry
+	lastHeader := ""
+
+	for _, raw := range lines {
+		line := strings.TrimSpace(raw)
+		if line == "" {
+			continue
+		}
+
+		dr, ok := findRange(line, now)
+		if !ok {
+			if len(entries) > 0 {
+				entries[len(entries)-1].text = append(entries[len(entries)-1].text, line)
+			}
+			if isBullet(line) {
+				lastHeader = ""
+			} else {
+				lastHeader = line
+			}
+			continue
+		}
+
+		title := strings.Trim(line[:dr.loc[0]]+" "+line[dr.loc[1]:], " \t|,;:-–—()[]")
+		// "Senior Engineer, Acme" on its own line above the dates
+		if title == "" && lastHeader != "" {
+			title = lastHeader
+			if n := len(entries); n > 0 {
+				prev := &entries[n-1]
+				if k := len(prev.text); k > 0 && prev.text[k-1] == lastHeader {
+					prev.text = prev.text[:k-1]
+				}
+			}
+		}
+		entries = append(entries, entry{dateRange: dr, title: title})
+		lastHeader = ""
+	}
+
+	positions := make([]Position, 0, len(entries))
+	intervals := make([]interval, 0, len(entries))
+	for _, en := range entries {
+		positions = append(positions, Position{
+			Title:   en.title,
+			Start:   en.start.String(),
+			End:     en.end.String(),
+			Current: en.current,
+			Months:  en.months(),
+			Skills:  e.skillIDs(en.title + "\n" + strings.Join(en.text, "\n")),
+		})
+		intervals = append(intervals, en.interval)
+	}
+	return positions, intervals
+}
+
+func isBullet(line string) bool {
+	return strings.IndexAny(line, "-*•·–▪") == 0
+}
+
+// skillIDs returns the canonical skills mentioned in text, in order.
+func (e *Extractor) skillIDs(text string) []string {
+	ids := []string{}
+	seen := make(map[string]bool)
+	for _, t := range e.analyzer.Analyze(text) {
+		if t.Keyword && !seen[t.Term] {
+			seen[t.Term] = true
+			ids = append(ids, t.Term)
+		}
+	}
+	return ids
+}
+
+// skillExperience merges, per skill, the intervals of every position that
+// mentions it.
+func (e *Extractor) skillExperience(positions []Position, intervals []interval) ([]SkillExperience, map[string]int) {
+	bySkill := make(map[string][]interval)
+	var order []string
+	for i, p := range positions {
+		for _, id := range p.Skills {
+			if _, ok := bySkill[id]; !ok {
+				order = append(order, id)
+			}
+			bySkill[id] = append(bySkill[id], intervals[i])
+		}
+	}
+
+	out := make([]SkillExperience, 0, len(order))
+	months := make(map[string]int, len(order))
+	for _, id := range order {
+		m := mergedMonths(bySkill[id])
+		months[id] = m
+		out = append(out, SkillExperience{Skill: id, Name: e.skillName(id), Months: m, Years: years(m)})
+	}
+	return out, months
+}
+
+func (e *Extractor) skillName(id string) string {
+	if s, ok := e.taxonomy.Lookup(id); ok {
+		return s.Name
+	}
+	return id
+}
+
+var numberWords = map[string]int{
+	"one": 1, "two": 2, "three": 3, "four": 4, "five": 5,
+	"six": 6, "seven": 7, "eight": 8, "nine": 9, "ten": 10,
+}
+
+// yearsRequirement matches "5+ years", "3-5 years", "at least two yrs"...
+var yearsRequirement = regexp.MustCompile(`(?i)\b(\d{1,2}|one|two|three|four|five|six|seven|eight|nine|ten)\s*(?:\+|plus)?\s*(?:(?:-|–|to)\s*\d{1,2}\s*\+?\s*)?(?:years?|yrs?)\b`)
+
+// requirements parses years-of-experience requirements from every
+// non-boilerplate line. Skills named in the same clause are attached to the
+// requirement; a clause without a skill is a total-experience requirement.
+func (e *Extractor) requirements(jobText string) []Requirement {
+	var out []Requirement
+	index := make(map[string]int)
+
+	for _, line := range jobpost.Parse(jobText).Lines {
+		if line.Group == jobpost.GroupBoilerplate {
+			continue
+		}
+		for _, m := range yearsRequirement.FindAllStringSubmatchIndex(line.Text, -1) {
+			n := parseCount(line.Text[m[2]:m[3]])
+			if n <= 0 {
+				continue
+			}
+
+			skills := e.skillIDs(clauseAfter(line.Text, m[1]))
+			if len(skills) == 0 {
+				skills = e.skillIDs(clauseBefore(line.Text, m[0]))
+			}
+			if len(skills) == 0 {
+				skills = []string{""}
+			}
+
+			for _, id := range skills {
+				req := Requirement{
+					Skill:    id,
+					Name:     "professional experience",
+					MinYears: float64(n),
+					Group:    line.Group,
+					Text:     line.Text,
+				}
+				if id != "" {
+					req.Name = e.skillName(id)
+				}
+
+				if i, ok := index[id]; ok {
+					if req.MinYears > out[i].MinYears {
+						out[i] = req
+					}
+					continue
+				}
+				index[id] = len(out)
+				out = append(out, req)
+			}
+		}
+	}
+	return out
+}
+
+func parseCount(s string) int {
+	if n, ok := numberWords[strings.ToLower(s)]; ok {
+		return n
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// clauseAfter returns the text from pos up to the end of the clause.
+func clauseAfter(line string, pos int) string {
+	rest := line[pos:]
+	if i := strings.IndexAny(rest, ".;"); i >= 0 {
+		rest = rest[:i]
+	}
+	return rest
+}
+
+// clauseBefore returns the text from the start of the clause up to pos.
+func clauseBefore(line string, pos int) string {
+	head := line[:pos]
+	if i := strings.LastIndexAny(head, ".;"); i >= 0 {
+		head = head[i+1:]
+	}
+	return head
+}
+
+var levelPatterns = []struct {
+	level Level
+	re    *regexp.Regexp
+}{
+	{LevelPrincipal, regexp.MustCompile(`(?i)\b(principal|distinguished)\b`)},
+	{LevelStaff, regexp.MustCompile(`(?i)\b(staff|architect)\b`)},
+	{LevelSenior, regexp.MustCompile(`(?i)\b(senior|sr\.?|lead)\b`)},
+	{LevelMid, regexp.MustCompile(`(?i)\b(mid[- ]level|intermediate|ii)\b`)},
+	{LevelJunior, regexp.MustCompile(`(?i)\b(junior|jr\.?|entry[- ]level|graduate|new grad)\b`)},
+	{LevelIntern, regexp.MustCompile(`(?i)\b(intern|internship|co-op)\b`)},
+}
+
+func levelOf(text string) Level {
+	for _, lp := range levelPatterns {
+		if lp.re.MatchString(text) {
+			return lp.level
+		}
+	}
+	return LevelUnknown
+}
+
+// seniority reads the level from the posting title (its first line) and
+// the highest level in the resume's position titles, falling back to
+// total years when titles carry no level.
+func seniority(jobText string, positions []Position, totalYears float64) Seniority {
+	var s Seniority
+	for _, line := range strings.Split(jobText, "\n") {
+		if strings.TrimSpace(line) != "" {
+			s.Job = levelOf(line)
+			break
+		}
+	}
+
+	for _, p := range positions {
+		if l := levelOf(p.Title); l > s.Resume {
+			s.Resume = l
+		}
+	}
+	if s.Resume == LevelUnknown && len(positions) > 0 {
+		switch {
+		case totalYears < 2:
+			s.Resume = LevelJunior
+		case totalYears < 5:
+			s.Resume = LevelMid
+		case totalYears < 8:
+			s.Resume = LevelSenior
+		default:
+			s.Resume = LevelStaff
+		}
+	}
+
+	s.Met = s.Job == LevelUnknown || s.Resume >= s.Job
+	return s
+}
+
+func years(months int) float64 {
+	return math.Round(float64(months)/12*10) / 10
+}
+
+func formatYears(y float64) string {
+	return strconv.FormatFloat(y, 'f', -1, 64)
+}
@@ -0,0 +1,94 @@
+package experience
+
+import "resume-tailor/internal/scoring/jobpost"
+
+// Level is a seniority level, ordered from intern to principal.
+type Level int
+
+const (
+	LevelUnknown Level = iota
+	LevelIntern
+	LevelJunior
+	LevelMid
+	LevelSenior
+	LevelStaff
+	LevelPrincipal
+)
+
+var levelNames = map[Level]string{
+	LevelUnknown:   "unknown",
+	LevelIntern:    "intern",
+	LevelJunior:    "junior",
+	LevelMid:       "mid",
+	LevelSenior:    "senior",
+	LevelStaff:     "staff",
+	LevelPrincipal: "principal",
+}
+
+func (l Level) String() string {
+	return levelNames[l]
+}
+
+func (l Level) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+// Position is one employment entry found in the resume.
+type Position struct {
+	Title   string   `json:"title"`
+	Start   string   `json:"start"`
+	End     string   `json:"end"`
+	Current bool     `json:"current"`
+	Months  int      `json:"months"`
+	Skills  []string `json:"skills"`
+}
+
+// SkillExperience is the evidenced duration for one canonical skill,
+// counting every position whose text mentions it.
+type SkillExperience struct {
+	Skill  string  `json:"skill"`
+	Name   string  `json:"name"`
+	Months int     `json:"months"`
+	Years  float64 `json:"years"`
+}
+
+// Requirement is an experience requirement parsed from the posting. An
+// empty Skill means total professional experience.
+type Requirement struct {
+	Skill          string        `json:"skill,omitempty"`
+	Name           string        `json:"name"`
+	MinYears       float64       `json:"min_years"`
+	Group          jobpost.Group `json:"group"`
+	Text           string        `json:"text"`
+	EvidencedYears float64       `json:"evidenced_years"`
+	Met            bool          `json:"met"`
+}
+
+// Gap is an unmet requirement with a human-readable explanation.
+type Gap struct {
+	Skill          string        `json:"skill,omitempty"`
+	Name           string        `json:"name"`
+	RequiredYears  float64       `json:"required_years"`
+	EvidencedYears float64       `json:"evidenced_years"`
+	Group          jobpost.Group `json:"group"`
+	Message        string        `json:"message"`
+}
+
+// Seniority compares the level asked for by the posting with the level
+// the resume supports.
+type Seniority struct {
+	Job    Level `json:"job"`
+	Resume Level `json:"resume"`
+	Met    bool  `json:"met"`
+}
+
+// Signals is the output of the experience extractor.
+type Signals struct {
+	Positions    []Position        `json:"positions"`
+	TotalMonths  int               `json:"total_months"`
+	TotalYears   float64           `json:"total_years"`
+	Skills       []SkillExperience `json:"skills"`
+	Requirements []Requirement     `json:"requirements"`
+	Gaps         []Gap             `json:"gaps"`
+	Seniority    Seniority         `json:"seniority"`
+}
@@ -0,0 +1,193 @@
+package jobpost
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Group classifies a part of a job posting.
+type Group string
+
+const (
+	GroupRequired         Group = "required"
+	GroupPreferred        Group = "preferred"
+	GroupResponsibilities Group = "responsibilities"
+	GroupGeneral          Group = "general"
+	GroupBoilerplate      Group = "boilerplate"
+)
+
+// Line is one line (or bullet) of the posting with its group.
+type Line struct {
+	Group   Group  `json:"group"`
+	Heading string `json:"heading,omitempty"`
+	Text    string `json:"text"`
+}
+
+// Posting is a job posting split into requirement groups.
+type Posting struct {
+	Lines []Line `json:"lines"`
+}
+
+// Text joins the lines that belong to any of the given groups.
+func (p Posting) Text(groups ...Group) string {
+	var b strings.Builder
+	for _, l := range p.Lines {
+		for _, g := range groups {
+			if l.Group == g {
+				b.WriteString(l.Text)
+				b.WriteString("\n")
+				break
+			}
+		}
+	}
+	return b.String()
+}
+
+// Count returns the number of lines in a group.
+func (p Posting) Count(group Group) int {
+	n := 0
+	for _, l := range p.Lines {
+		if l.Group == group {
+			n++
+		}
+	}
+	return n
+}
+
+var headingGroups = []struct {
+	group    Group
+	keywords []string
+}{
+	// Order matters: "preferred qualifications" must win over "qualifications"
+	{GroupPreferred, []string{"nice to have", "nice-to-have", "preferred", "bonus", "bonus points", "pluses", "good to have", "desirable", "extra credit"}},
+	{GroupBoilerplate, []string{"benefits", "perks", "what we offer", "compensation", "salary", "equal opportunity", "eeo", "about us", "about the company", "who we are", "our values", "diversity", "how to apply", "why join", "why work"}},
+	{GroupResponsibilities, []string{"responsibilities", "what you'll do", "what you will do", "the role", "your role", "day to day", "day-to-day", "duties", "your impact", "what you'll be doing", "in this role"}},
+	{GroupRequired, []string{"requirements", "qualifications", "what you'll need", "what you need", "what you will need", "must have", "must-have", "you have", "what we're looking for", "what we are looking for", "who you are", "required skills", "technical skills", "key skills", "required experience", "skills and experience", "skills & experience", "experience and skills"}},
+}
+
+var (
+	preferredPhrases   = []string{"nice to have", "nice-to-have", "bonus", "is a plus", "a plus", "preferred", "ideally", "desirable", "not required", "good to have"}
+	requiredPhrases    = []string{"must have", "must-have", "required", "requirement", "at least", "minimum", "proficien", "you have", "you will need", "strong experience"}
+	boilerplatePhrases = []string{"equal opportunity", "without regard to", "race, color", "sexual orientation", "gender identity", "reasonable accommodation", "e-verify", "401(k)", "401k", "health insurance", "dental insurance", "paid time off", "parental leave", "background check", "visa sponsorship", "salary range", "compensation range", "apply now"}
+)
+
+// yearsPattern matches "5+ years", "3-5 years", "at least 2 yrs"...
+var yearsPattern = regexp.MustCompile(`(?i)\b\d+\s*(\+|-\s*\d+)?\s*(years?|yrs?)\b`)
+
+// Parse splits a posting into lines and assigns each to a requirement group.
+// Headings set the group for the lines below them; phrases inside a line
+// ("nice to have", "5+ years", EEO wording) can override it.
+func Parse(text string) Posting {
+	var p Posting
+	current := GroupGeneral
+	heading := ""
+
+	for _, raw := range strings.Split(text, "\n") {
+		line := strings.TrimSpace(raw)
+		if line == "" {
+			continue
+		}
+
+		group, lineHeading := current, heading
+		if g, h, rest, ok := parseHeading(line); ok {
+			if rest == "" {
+				current, heading = g, h
+				continue
+			}
+			// Inline headings only apply to their own line
+			group, lineHeading, line = g, h, rest
+		}
+
+		line = trimBullet(line)
+		if line == "" {
+			continue
+		}
+		p.Lines = append(p.Lines, Line{
+			Group:   classifyLine(line, group),
+			Heading: lineHeading,
+			Text:    line,
+		})
+	}
+
+	return p
+}
+
+// parseHeading recognizes a heading line, optionally followed by inline
+// content after a colon ("Nice to have: Kafka, Redis"). A bare line only
+// counts as a heading when it ends with a known heading phrase, so that
+// "Strong communication skills" stays a requirement.
+func parseHeading(line string) (Group, string, string, bool) {
+	head, rest := line, ""
+	colon := false
+	if idx := strings.Index(line, ":"); idx > 0 {
+		head, rest = line[:idx], strings.TrimSpace(line[idx+1:])
+		colon = true
+	}
+
+	head = strings.TrimFunc(head, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+	})
+	if head == "" || len(strings.Fields(head)) > 6 || strings.ContainsAny(head, "0123456789") {
+		return "", "", "", false
+	}
+
+	lower := strings.ToLower(strings.ReplaceAll(head, "’", "'"))
+	if !colon && !endsWithHeadingPhrase(lower) {
+		return "", "", "", false
+	}
+
+	for _, hg := range headingGroups {
+		for _, kw := range hg.keywords {
+			if strings.Contains(lower, kw) {
+				return hg.group, head, rest, true
+			}
+		}
+	}
+	return "", "", "", false
+}
+
+func endsWithHeadingPhrase(lower string) bool {
+	for _, hg := range headingGroups {
+		for _, kw := range hg.keywords {
+			if strings.HasSuffix(lower, kw) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func classifyLine(line string, current Group) Group {
+	lower := strings.ToLower(line)
+
+	if containsAny(lower, boilerplatePhrases) {
+		return GroupBoilerplate
+	}
+	if current == GroupBoilerplate {
+		return current
+	}
+	if containsAny(lower, preferredPhrases) {
+		return GroupPreferred
+	}
+	if current == GroupPreferred {
+		return current
+	}
+	if containsAny(lower, requiredPhrases) || yearsPattern.MatchString(line) {
+		return GroupRequired
+	}
+	return current
+}
+
+func containsAny(s string, phrases []string) bool {
+	for _, p := range phrases {
+		if strings.Contains(s, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func trimBullet(line string) string {
+	return strings.TrimSpace(strings.TrimLeft(line, "-*•·–▪ \t"))
+}
@@ -0,0 +1,43 @@
+package scoring
+
+import (
+	"time"
+
+	"resume-tailor/internal/scoring/bm25"
+	"resume-tailor/internal/scoring/experience"
+	"resume-tailor/internal/scoring/taxonomy"
+)
+
+// Signals is everything the deterministic scoring stage knows about a
+// resume/posting pair.
+type Signals struct {
+	BM25       bm25.Signals       `json:"bm25"`
+	Experience experience.Signals `json:"experience"`
+}
+
+// Scorer runs the keyword and experience scorers with a shared taxonomy.
+type Scorer struct {
+	bm25       *bm25.Scorer
+	experience *experience.Extractor
+}
+
+// NewScorer creates a Scorer with default BM25 parameters.
+func NewScorer(tax *taxonomy.Taxonomy) *Scorer {
+	return &Scorer{
+		bm25:       bm25.NewDefaultScorer(tax),
+		experience: experience.NewExtractor(tax),
+	}
+}
+
+// Compute scores the resume against the posting. now anchors open-ended
+// date ranges such as "Jan 2019 – Present".
+func (s *Scorer) Compute(resumeText, jobText string, now time.Time) (Signals, error) {
+	keywords, err := s.bm25.Compute(resumeText, jobText)
+	if err != nil {
+		return Signals{}, err
+	}
+	return Signals{
+		BM25:       keywords,
+		Experience: s.experience.Extract(resumeText, jobText, now),
+	}, nil
+}
@@ -0,0 +1,145 @@
+package sections
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Kind identifies a resume section.
+type Kind string
+
+const (
+	KindHeader         Kind = "header"
+	KindSummary        Kind = "summary"
+	KindExperience     Kind = "experience"
+	KindEducation      Kind = "education"
+	KindSkills         Kind = "skills"
+	KindProjects       Kind = "projects"
+	KindCertifications Kind = "certifications"
+	KindOther          Kind = "other"
+)
+
+// Section is a contiguous block of resume text under one heading. Text
+// preceding the first heading (name, contact info) is KindHeader.
+type Section struct {
+	Kind      Kind   `json:"kind"`
+	Heading   string `json:"heading"`
+	Text      string `json:"text"`
+	StartLine int    `json:"start_line"`
+}
+
+// headings maps normalized heading text to a section kind.
+var headings = map[string]Kind{
+	"summary":                     KindSummary,
+	"professional summary":        KindSummary,
+	"profile":                     KindSummary,
+	"professional profile":        KindSummary,
+	"about":                       KindSummary,
+	"about me":                    KindSummary,
+	"objective":                   KindSummary,
+	"career objective":            KindSummary,
+	"experience":                  KindExperience,
+	"work experience":             KindExperience,
+	"professional experience":     KindExperience,
+	"relevant experience":         KindExperience,
+	"employment":                  KindExperience,
+	"employment history":          KindExperience,
+	"work history":                KindExperience,
+	"career history":              KindExperience,
+	"education":                   KindEducation,
+	"academic background":         KindEducation,
+	"education and training":      KindEducation,
+	"skills":                      KindSkills,
+	"technical skills":            KindSkills,
+	"core skills":                 KindSkills,
+	"key skills":                  KindSkills,
+	"core competencies":           KindSkills,
+	"competencies":                KindSkills,
+	"technologies":                KindSkills,
+	"tech stack":                  KindSkills,
+	"tools and technologies":      KindSkills,
+	"projects":                    KindProjects,
+	"personal projects":           KindProjects,
+	"side projects":               KindProjects,
+	"selected projects":           KindProjects,
+	"open source":                 KindProjects,
+	"certifications":              KindCertifications,
+	"certificates":                KindCertifications,
+	"licenses and certifications": KindCertifications,
+	"certifications and licenses": KindCertifications,
+	"interests":                   KindOther,
+	"hobbies":                     KindOther,
+	"hobbies and interests":       KindOther,
+	"volunteering":                KindOther,
+	"volunteer experience":        KindOther,
+	"awards":                      KindOther,
+	"honors and awards":           KindOther,
+	"publications":                KindOther,
+	"languages":                   KindOther,
+	"references":                  KindOther,
+}
+
+// Detect splits resume text into sections using heading heuristics: a short
+// line on its own whose normalized text is a known section title.
+func Detect(text string) []Section {
+	var out []Section
+	current := Section{Kind: KindHeader}
+	var body []string
+
+	flush := func() {
+		current.Text = strings.TrimSpace(strings.Join(body, "\n"))
+		if current.Text != "" || current.Heading != "" {
+			out = append(out, current)
+		}
+		body = nil
+	}
+
+	for i, line := range strings.Split(text, "\n") {
+		if kind, heading, ok := parseHeading(line); ok {
+			flush()
+			current = Section{Kind: kind, Heading: heading, StartLine: i}
+			continue
+		}
+		// Inline headings: "Skills: Go, SQL, Docker"
+		if idx := strings.Index(line, ":"); idx > 0 {
+			if kind, heading, ok := parseHeading(line[:idx]); ok {
+				flush()
+				current = Section{Kind: kind, Heading: heading, StartLine: i}
+				body = append(body, line[idx+1:])
+				continue
+			}
+		}
+		body = append(body, line)
+	}
+	flush()
+
+	return out
+}
+
+// parseHeading recognizes decorated headings such as "EXPERIENCE",
+// "## Skills", "Projects:" or "--- Education ---".
+func parseHeading(line string) (Kind, string, bool) {
+	trimmed := strings.TrimSpace(line)
+	if trimmed == "" || len(trimmed) > 40 {
+		return "", "", false
+	}
+
+	heading := strings.TrimFunc(trimmed, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if heading == "" || len(strings.Fields(heading)) > 4 {
+		return "", "", false
+	}
+
+	kind, ok := headings[normalize(heading)]
+	if !ok {
+		return "", "", false
+	}
+	return kind, heading, true
+}
+
+func normalize(heading string) string {
+	heading = strings.ToLower(heading)
+	heading = strings.ReplaceAll(heading, "&", " and ")
+	return strings.Join(strings.Fields(heading), " ")
+}
@@ -0,0 +1,202 @@
+package taxonomy
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"resume-tailor/internal/scoring/analysis"
+)
+
+//go:embed skills.json
+var defaultData []byte
+
+// Skill is a canonical skill with the aliases that should normalize to it.
+// Cased skills have an ID or name that is also an ordinary word ("go");
+// those only match spelled exactly as Name ("Go"), while their aliases
+// still match in any case.
+type Skill struct {
+	ID       string   `json:"id"`
+	Name     string   `json:"name"`
+	Category string   `json:"category"`
+	Cased    bool     `json:"cased,omitempty"`
+	Aliases  []string `json:"aliases"`
+}
+
+// Taxonomy is a versioned set of skills indexed by alias.
+type Taxonomy struct {
+	Version string  `json:"version"`
+	Skills  []Skill `json:"skills"`
+
+	byID    map[string]Skill
+	aliases map[string]string // space-joined alias tokens -> skill ID
+	cased   map[string]string // space-joined name surfaces -> skill ID
+	maxLen  int
+}
+
+// Default returns the taxonomy bundled with the binary.
+func Default() (*Taxonomy, error) {
+	return Parse(defaultData)
+}
+
+// Load reads a taxonomy from a JSON file so it can be extended without
+// recompiling. An empty path falls back to the bundled taxonomy.
+func Load(path string) (*Taxonomy, error) {
+	if path == "" {
+		return Default()
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read skills taxonomy: %w", err)
+	}
+	return Parse(data)
+}
+
+// Parse decodes and indexes a taxonomy. Every skill matches its own ID and
+// name in addition to its aliases, case-sensitively for cased skills.
+func Parse(data []byte) (*Taxonomy, error) {
+	var t Taxonomy
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("failed to parse skills taxonomy: %w", err)
+	}
+	if strings.TrimSpace(t.Version) == "" {
+		return nil, fmt.Errorf("skills taxonomy: version is required")
+	}
+
+	t.byID = make(map[string]Skill, len(t.Skills))
+	t.aliases = make(map[string]string)
+	t.cased = make(map[string]string)
+	tokenizer := analysis.NewStandardTokenizer()
+
+	for _, s := range t.Skills {
+		if s.ID == "" || s.Name == "" {
+			return nil, fmt.Errorf("skills taxonomy: skill id and name are required")
+		}
+		if _, dup := t.byID[s.ID]; dup {
+			return nil, fmt.Errorf("skills taxonomy: duplicate skill id %q", s.ID)
+		}
+		t.byID[s.ID] = s
+
+		aliases := append([]string{s.ID, s.Name}, s.Aliases...)
+		if s.Cased {
+			key := surfaceKey(tokenizer, s.Name)
+			if other, ok := t.cased[key]; ok && other != s.ID {
+				return nil, fmt.Errorf("skills taxonomy: name %q maps to both %q and %q", s.Name, other, s.ID)
+			}
+			t.cased[key] = s.ID
+			t.index(key)
+			aliases = s.Aliases
+		}
+
+		for _, alias := range aliases {
+			key := aliasKey(tokenizer, alias)
+			if key == "" {
+				continue
+			}
+			if other, ok := t.aliases[key]; ok && other != s.ID {
+				return nil, fmt.Errorf("skills taxonomy: alias %q maps to both %q and %q", alias, other, s.ID)
+			}
+			t.aliases[key] = s.ID
+			t.index(key)
+		}
+	}
+
+	return &t, nil
+}
+
+// index records the length of an alias key for match.
+func (t *Taxonomy) index(key string) {
+	if n := strings.Count(key, " ") + 1; n > t.maxLen {
+		t.maxLen = n
+	}
+}
+
+func surfaceKey(tokenizer analysis.Tokenizer, name string) string {
+	tokens := tokenizer.Tokenize(name)
+	parts := make([]string, len(tokens))
+	for i, tok := range tokens {
+		parts[i] = tok.Surface
+	}
+	return strings.Join(parts, " ")
+}
+
+func aliasKey(tokenizer analysis.Tokenizer, alias string) string {
+	tokens := tokenizer.Tokenize(alias)
+	parts := make([]string, len(tokens))
+	for i, tok := range tokens {
+		parts[i] = strings.ToLower(tok.Term)
+	}
+	return strings.Join(parts, " ")
+}
+
+// Lookup returns the skill for a canonical ID.
+func (t *Taxonomy) Lookup(id string) (Skill, bool) {
+	s, ok := t.byID[id]
+	return s, ok
+}
+
+// Filter returns an analysis stage that replaces the longest alias match at
+// each position with a single keyword token whose term is the skill ID. It
+// expects lowercased terms and must run before stopword removal.
+func (t *Taxonomy) Filter() analysis.Filter {
+	return analysis.FilterFunc(func(tokens []analysis.Token) []analysis.Token {
+		out := make([]analysis.Token, 0, len(tokens))
+		for i := 0; i < len(tokens); {
+			id, n := t.match(tokens[i:])
+			if n == 0 {
+				out = append(out, tokens[i])
+				i++
+				continue
+			}
+
+			surfaces := make([]string, n)
+			for j, tok := range tokens[i : i+n] {
+				surfaces[j] = tok.Surface
+			}
+			out = append(out, analysis.Token{
+				Term:     id,
+				Surface:  strings.Join(surfaces, " "),
+				Position: tokens[i].Position,
+				Keyword:  true,
+			})
+			i += n
+		}
+		return out
+	})
+}
+
+// match finds the longest alias starting at tokens[0] made of adjacent
+// tokens, comparing cased skill names against the original spelling.
+func (t *Taxonomy) match(tokens []analysis.Token) (string, int) {
+	limit := t.maxLen
+	if len(tokens) < limit {
+		limit = len(tokens)
+	}
+
+	for n := limit; n > 0; n-- {
+		parts := make([]string, n)
+		surfaces := make([]string, n)
+		adjacent := true
+		for j := 0; j < n; j++ {
+			if j > 0 && tokens[j].Position != tokens[j-1].Position+1 {
+				adjacent = false
+				break
+			}
+			parts[j] = tokens[j].Term
+			surfaces[j] = tokens[j].Surface
+		}
+		if !adjacent {
+			continue
+		}
+		if id, ok := t.aliases[strings.Join(parts, " ")]; ok {
+			return id, n
+		}
+		if id, ok := t.cased[strings.Join(surfaces, " ")]; ok {
+			return id, n
+		}
+	}
+	return "", 0
+}
@@ -0,0 +1,75 @@
+package taxonomy
+
+import (
+	"reflect"
+	"testing"
+
+	"resume-tailor/internal/scoring/analysis"
+)
+
+// skills returns the skill IDs the default analyzer finds in text.
+func skills(t *testing.T, tax *Taxonomy, text string) []string {
+	t.Helper()
+	ids := []string{}
+	for _, tok := range analysis.Default(tax.Filter()).Analyze(text) {
+		if tok.Keyword {
+			ids = append(ids, tok.Term)
+		}
+	}
+	return ids
+}
+
+func TestFilter(t *testing.T) {
+	tax, err := Default()
+	if err != nil {
+		t.Fatalf("Default: %v", err)
+	}
+
+	tests := []struct {
+		text string
+		want []string
+	}{
+		// Ordinary words and short aliases are not skills
+		{"Spring 2020 internship", []string{}},
+		{"Please express interest by Friday", []string{}},
+		{"Express interest in the role", []string{}},
+		{"ready to go with each node of the graph", []string{}},
+		{"a swift response to an elastic demand", []string{}},
+		{"rust on the ruby ring, an agile cat", []string{}},
+		{"lambda calculus and torch relay", []string{}},
+		{"tf pg ml dl ror ts py sh", []string{}},
+		{"shipping containers", []string{}},
+		{"open source work on GitHub and GitLab", []string{}},
+
+		// Cased names, aliases in any case
+		{"Go, Golang and go lang", []string{"go", "go", "go"}},
+		{"Spring Boot and the Spring Framework", []string{"spring", "spring"}},
+		{"Express.js APIs on Node.js", []string{"express", "nodejs"}},
+		{"Swift, Ruby and Rust", []string{"swift", "ruby", "rust"}},
+		{"AWS Lambda and aws lambda", []string{"lambda", "lambda"}},
+		{"Agile teams running Scrum", []string{"agile", "agile"}},
+		{"Machine Learning with PyTorch and Docker", []string{"machine_learning", "pytorch", "docker"}},
+	}
+	for _, tt := range tests {
+		if got := skills(t, tax, tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q: skills %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"missing version", `{"skills": []}`},
+		{"missing name", `{"version": "1", "skills": [{"id": "go"}]}`},
+		{"duplicate id", `{"version": "1", "skills": [{"id": "go", "name": "Go"}, {"id": "go", "name": "Golang"}]}`},
+		{"shared alias", `{"version": "1", "skills": [{"id": "go", "name": "Go", "aliases": ["gl"]}, {"id": "gitlab", "name": "GitLab", "aliases": ["gl"]}]}`},
+	}
+	for _, tt := range tests {
+		if _, err := Parse([]byte(tt.data)); err == nil {
+			t.Errorf("%s: Parse succeeded, want an error", tt.name)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package usage
+
+import (
+	"sort"
+	"strings"
+)
+
+// Price is the cost of a model in USD per million tokens.
+type Price struct {
+	InputPerMTok  float64
+	OutputPerMTok float64
+}
+
+// defaultPrices are list prices keyed by model name prefix, so dated and
+// "-latest" variants share an entry. Local and fake models are free.
+var defaultPrices = map[string]Price{
+	"gpt-4o-mini":       {InputPerMTok: 0.15, OutputPerMTok: 0.60},
+	"gpt-4o":            {InputPerMTok: 2.50, OutputPerMTok: 10.00},
+	"gpt-4.1-nano":      {InputPerMTok: 0.10, OutputPerMTok: 0.40},
+	"gpt-4.1-mini":      {InputPerMTok: 0.40, OutputPerMTok: 1.60},
+	"gpt-4.1":           {InputPerMTok: 2.00, OutputPerMTok: 8.00},
+	"o1-mini":           {InputPerMTok: 1.10, OutputPerMTok: 4.40},
+	"o1":                {InputPerMTok: 15.00, OutputPerMTok: 60.00},
+	"o3-mini":           {InputPerMTok: 1.10, OutputPerMTok: 4.40},
+	"o3":                {InputPerMTok: 2.00, OutputPerMTok: 8.00},
+	"o4-mini":           {InputPerMTok: 1.10, OutputPerMTok: 4.40},
+	"claude-3-5-haiku":  {InputPerMTok: 0.80, OutputPerMTok: 4.00},
+	"claude-3-5-sonnet": {InputPerMTok: 3.00, OutputPerMTok: 15.00},
+	"claude-3-7-sonnet": {InputPerMTok: 3.00, OutputPerMTok: 15.00},
+	"claude-sonnet-4":   {InputPerMTok: 3.00, OutputPerMTok: 15.00},
+	"claude-3-opus":     {InputPerMTok: 15.00, OutputPerMTok: 75.00},
+	"fake":              {},
+}
+
+// Pricing looks up model prices. An override, when set, applies to every
+// model; it is meant for deployments running a single unlisted model.
+type Pricing struct {
+	prefixes []string
+	prices   map[string]Price
+	override *Price
+}
+
+// NewPricing creates a Pricing from the bundled price list.
+func NewPricing(override *Price) *Pricing {
+	prefixes := make([]string, 0, len(defaultPrices))
+	for p := range defaultPrices {
+		prefixes = append(prefixes, p)
+	}
+	// Longest prefix first so gpt-4o-mini wins over gpt-4o
+	sort.Slice(prefixes, func(i, j int) bool { return len(prefixes[i]) > len(prefixes[j]) })
+
+	return &Pricing{prefixes: prefixes, prices: defaultPrices, override: override}
+}
+
+// Lookup returns the price of model, or false when it is unknown.
+func (p *Pricing) Lookup(model string) (Price, bool) {
+	if p.override != nil {
+		return *p.override, true
+	}
+	model = strings.ToLower(model)
+	for _, prefix := range p.prefixes {
+		if strings.HasPrefix(model, prefix) {
+			return p.prices[prefix], true
+		}
+	}
+	return Price{}, false
+}
+
+// Cost returns the USD cost of a call; unknown models cost 0.
+func (p *Pricing) Cost(model string, inputTokens, outputTokens int) float64 {
+	price, _ := p.Lookup(model)
+	return (float64(inputTokens)*price.InputPerMTok + float64(outputTokens)*price.OutputPerMTok) / 1e6
+}
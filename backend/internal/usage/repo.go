@@ -0,0 +1,153 @@
+package usage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Repo struct {
+	db *pgxpool.Pool
+}
+
+func NewRepo(db *pgxpool.Pool) *Repo {
+	return &Repo{db: db}
+}
+
+// InsertCalls stores LLM calls and a matching usage event for each, in one
+// transaction.
+func (r *Repo) InsertCalls(ctx context.Context, calls []Call) error {
+	if len(calls) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	const insertCallQ = `
+INSERT INTO llm_calls (run_id, user_id, provider, model, prompt_version, attempt, input_tokens, output_tokens, cost_usd, latency_ms, error)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+
+	const insertEventQ = `
+INSERT INTO usage_events (user_id, run_id, event_type)
+VALUES ($1, $2, $3)`
+
+	for _, c := range calls {
+		if c.RunID == uuid.Nil {
+			return fmt.Errorf("bad input: run_id")
+		}
+		if c.UserID == uuid.Nil {
+			return fmt.Errorf("bad input: user_id")
+		}
+
+		if _, err := tx.Exec(ctx, insertCallQ,
+			c.RunID,
+			c.UserID,
+			c.Provider,
+			c.Model,
+			c.PromptVersion,
+			c.Attempt,
+			c.InputTokens,
+			c.OutputTokens,
+			c.CostUSD,
+			c.LatencyMS,
+			c.Error,
+		); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, insertEventQ, c.UserID, c.RunID, EventLLMCall); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// BillingPeriod returns the current period of the user's active or
+// trialing subscription, or the calendar month (UTC) containing now when
+// there is none.
+func (r *Repo) BillingPeriod(ctx context.Context, userID uuid.UUID, now time.Time) (Period, error) {
+	if userID == uuid.Nil {
+		return Period{}, fmt.Errorf("bad input: user_id")
+	}
+
+	const q = `
+SELECT current_period_start, current_period_end
+FROM subscriptions
+WHERE user_id = $1
+  AND status IN ('active', 'trialing')
+  AND current_period_start <= $2
+  AND current_period_end > $2`
+
+	var p Period
+	err := r.db.QueryRow(ctx, q, userID, now).Scan(&p.Start, &p.End)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return CalendarMonth(now), nil
+		}
+		return Period{}, err
+	}
+
+	return p, nil
+}
+
+// UserSpend sums the user's LLM calls in the period.
+func (r *Repo) UserSpend(ctx context.Context, userID uuid.UUID, period Period) (Spend, error) {
+	if userID == uuid.Nil {
+		return Spend{}, fmt.Errorf("bad input: user_id")
+	}
+
+	const q = `
+SELECT count(*), COALESCE(sum(input_tokens), 0), COALESCE(sum(output_tokens), 0), COALESCE(sum(cost_usd), 0)::float8
+FROM llm_calls
+WHERE user_id = $1 AND created_at >= $2 AND created_at < $3`
+
+	spend := Spend{Period: period}
+	err := r.db.QueryRow(ctx, q, userID, period.Start, period.End).Scan(
+		&spend.Calls,
+		&spend.InputTokens,
+		&spend.OutputTokens,
+		&spend.CostUSD,
+	)
+	if err != nil {
+		return Spend{}, err
+	}
+
+	return spend, nil
+}
+
+// TotalSpend sums every LLM call in the period.
+func (r *Repo) TotalSpend(ctx context.Context, period Period) (Spend, error) {
+	const q = `
+SELECT count(*), COALESCE(sum(input_tokens), 0), COALESCE(sum(output_tokens), 0), COALESCE(sum(cost_usd), 0)::float8
+FROM llm_calls
+WHERE created_at >= $1 AND created_at < $2`
+
+	spend := Spend{Period: period}
+	err := r.db.QueryRow(ctx, q, period.Start, period.End).Scan(
+		&spend.Calls,
+		&spend.InputTokens,
+		&spend.OutputTokens,
+		&spend.CostUSD,
+	)
+	if err != nil {
+		return Spend{}, err
+	}
+
+	return spend, nil
+}
+
+// CalendarMonth returns the UTC calendar month containing t.
+func CalendarMonth(t time.Time) Period {
+	t = t.UTC()
+	start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return Period{Start: start, End: start.AddDate(0, 1, 0)}
+}
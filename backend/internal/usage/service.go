@@ -0,0 +1,134 @@
+package usage
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"resume-tailor/internal/ai"
+
+	"github.com/google/uuid"
+)
+
+type Service struct {
+	repo    *Repo
+	pricing *Pricing
+	budgets Budgets
+
+	mu       sync.Mutex
+	unpriced map[string]bool
+}
+
+func NewService(repo *Repo, pricing *Pricing, budgets Budgets) *Service {
+	return &Service{
+		repo:     repo,
+		pricing:  pricing,
+		budgets:  budgets,
+		unpriced: make(map[string]bool),
+	}
+}
+
+// RecordCalls prices and stores the provider calls made for a run.
+func (s *Service) RecordCalls(ctx context.Context, runID, userID uuid.UUID, promptVersion string, calls []ai.CallUsage) error {
+	if runID == uuid.Nil {
+		return fmt.Errorf("bad input: run_id")
+	}
+	if userID == uuid.Nil {
+		return fmt.Errorf("bad input: user_id")
+	}
+
+	rows := make([]Call, 0, len(calls))
+	for _, c := range calls {
+		s.warnUnpriced(c.Model)
+
+		row := Call{
+			RunID:         runID,
+			UserID:        userID,
+			Provider:      c.Provider,
+			Model:         c.Model,
+			PromptVersion: promptVersion,
+			Attempt:       c.Attempt,
+			InputTokens:   c.InputTokens,
+			OutputTokens:  c.OutputTokens,
+			CostUSD:       s.pricing.Cost(c.Model, c.InputTokens, c.OutputTokens),
+			LatencyMS:     c.Latency.Milliseconds(),
+		}
+		if c.Err != nil {
+			msg := c.Err.Error()
+			row.Error = &msg
+		}
+		rows = append(rows, row)
+	}
+
+	return s.repo.InsertCalls(ctx, rows)
+}
+
+// CheckBudget returns an error wrapping ErrBudgetExceeded when the user's
+// billing period spend or the deployment's monthly spend has reached its
+// budget.
+func (s *Service) CheckBudget(ctx context.Context, userID uuid.UUID, now time.Time) error {
+	if userID == uuid.Nil {
+		return fmt.Errorf("bad input: user_id")
+	}
+
+	if limit := s.budgets.DeploymentMonthlyUSD; limit > 0 {
+		spend, err := s.repo.TotalSpend(ctx, CalendarMonth(now))
+		if err != nil {
+			return fmt.Errorf("failed to load deployment LLM spend: %w", err)
+		}
+		if spend.CostUSD >= limit {
+			return fmt.Errorf("%w: deployment spent $%.2f of its $%.2f monthly budget", ErrBudgetExceeded, spend.CostUSD, limit)
+		}
+	}
+
+	if limit := s.budgets.UserMonthlyUSD; limit > 0 {
+		spend, err := s.UserSpend(ctx, userID, now)
+		if err != nil {
+			return err
+		}
+		if spend.CostUSD >= limit {
+			return fmt.Errorf("%w: user spent $%.2f of their $%.2f budget for the period ending %s",
+				ErrBudgetExceeded, spend.CostUSD, limit, spend.Period.End.Format("2006-01-02"))
+		}
+	}
+
+	return nil
+}
+
+// UserSpend returns the user's spend in their current billing period.
+func (s *Service) UserSpend(ctx context.Context, userID uuid.UUID, now time.Time) (Spend, error) {
+	if userID == uuid.Nil {
+		return Spend{}, fmt.Errorf("bad input: user_id")
+	}
+
+	period, err := s.repo.BillingPeriod(ctx, userID, now)
+	if err != nil {
+		return Spend{}, fmt.Errorf("failed to load billing period: %w", err)
+	}
+	spend, err := s.repo.UserSpend(ctx, userID, period)
+	if err != nil {
+		return Spend{}, fmt.Errorf("failed to load LLM spend: %w", err)
+	}
+	return spend, nil
+}
+
+// Budgets returns the configured limits.
+func (s *Service) Budgets() Budgets {
+	return s.budgets
+}
+
+// warnUnpriced logs once per model that has no known price, since its
+// calls are recorded at zero cost and do not count toward budgets.
+func (s *Service) warnUnpriced(model string) {
+	if _, ok := s.pricing.Lookup(model); ok {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.unpriced[model] {
+		s.unpriced[model] = true
+		slog.Warn("no price known for LLM model, recording zero cost", "model", model)
+	}
+}
@@ -0,0 +1,56 @@
+package usage
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EventLLMCall is the usage_events type written for every LLM call.
+const EventLLMCall = "llm_call"
+
+// Call is one billed LLM request made for a run.
+type Call struct {
+	ID            uuid.UUID
+	RunID         uuid.UUID
+	UserID        uuid.UUID
+	Provider      string
+	Model         string
+	PromptVersion string
+	Attempt       int
+	InputTokens   int
+	OutputTokens  int
+	CostUSD       float64
+	LatencyMS     int64
+	Error         *string
+	CreatedAt     time.Time
+}
+
+// Period is a half-open billing period [Start, End).
+type Period struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Spend aggregates LLM calls over a period.
+type Spend struct {
+	Period       Period
+	Calls        int
+	InputTokens  int64
+	OutputTokens int64
+	CostUSD      float64
+}
+
+// Budgets are monthly spend limits in USD; zero means unlimited. User
+// budgets apply per billing period, the deployment budget per calendar
+// month (UTC).
+type Budgets struct {
+	UserMonthlyUSD       float64
+	DeploymentMonthlyUSD float64
+}
+
+var (
+	ErrBudgetExceeded = errors.New("LLM budget exceeded")
+	ErrBadInput       = errors.New("bad input")
+)
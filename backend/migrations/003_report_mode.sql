@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Record whether a report came from the LLM or the deterministic scorer
+ALTER TABLE run_reports
+  ADD COLUMN IF NOT EXISTS mode TEXT NOT NULL DEFAULT 'llm'
+    CHECK (mode IN ('llm', 'deterministic'));
+
+-- +goose StatementEnd
+
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE run_reports
+  DROP COLUMN IF EXISTS mode;
+
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Record which prompt, model and provider produced each LLM report
+ALTER TABLE run_reports
+  ADD COLUMN IF NOT EXISTS prompt_version TEXT,
+  ADD COLUMN IF NOT EXISTS model TEXT,
+  ADD COLUMN IF NOT EXISTS provider TEXT;
+
+-- +goose StatementEnd
+
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE run_reports
+  DROP COLUMN IF EXISTS prompt_version,
+  DROP COLUMN IF EXISTS model,
+  DROP COLUMN IF EXISTS provider;
+
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Version of the ats_report/change_plan JSON shape; existing rows are v1
+ALTER TABLE run_reports
+  ADD COLUMN IF NOT EXISTS schema_version INT NOT NULL DEFAULT 1;
+
+-- +goose StatementEnd
+
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE run_reports
+  DROP COLUMN IF EXISTS schema_version;
+
+-- +goose StatementEnd
@@ -0,0 +1,48 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Validated LLM replies keyed by a hash of the normalized resume and job
+-- text, provider, model, prompt version and temperature
+CREATE TABLE IF NOT EXISTS llm_cache (
+  key            TEXT PRIMARY KEY,
+  provider       TEXT NOT NULL,
+  model          TEXT NOT NULL,
+  prompt_version TEXT NOT NULL,
+  schema_version INT NOT NULL,
+  temperature    DOUBLE PRECISION,
+  content        JSONB NOT NULL,
+  input_tokens   INT NOT NULL DEFAULT 0,
+  output_tokens  INT NOT NULL DEFAULT 0,
+  hits           INT NOT NULL DEFAULT 0,
+  last_hit_at    TIMESTAMPTZ,
+  created_at     TIMESTAMPTZ NOT NULL DEFAULT now(),
+  expires_at     TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_llm_cache_expires_at ON llm_cache(expires_at);
+
+-- Force a fresh LLM call for a run even when a cached reply exists
+ALTER TABLE runs
+  ADD COLUMN IF NOT EXISTS bypass_cache BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Which cache entry a report was written to or served from
+ALTER TABLE run_reports
+  ADD COLUMN IF NOT EXISTS cache_key TEXT,
+  ADD COLUMN IF NOT EXISTS cache_hit BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +goose StatementEnd
+
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE run_reports
+  DROP COLUMN IF EXISTS cache_key,
+  DROP COLUMN IF EXISTS cache_hit;
+
+ALTER TABLE runs
+  DROP COLUMN IF EXISTS bypass_cache;
+
+DROP TABLE IF EXISTS llm_cache;
+
+-- +goose StatementEnd
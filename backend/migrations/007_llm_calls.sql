@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- One row per LLM request, including failed and repair attempts
+CREATE TABLE IF NOT EXISTS llm_calls (
+  id             UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  run_id         UUID NOT NULL REFERENCES runs(id) ON DELETE CASCADE,
+  user_id        UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  provider       TEXT NOT NULL,
+  model          TEXT NOT NULL,
+  prompt_version TEXT NOT NULL,
+  attempt        INT NOT NULL,
+  input_tokens   INT NOT NULL DEFAULT 0,
+  output_tokens  INT NOT NULL DEFAULT 0,
+  cost_usd       NUMERIC(12, 6) NOT NULL DEFAULT 0,
+  latency_ms     BIGINT NOT NULL DEFAULT 0,
+  error          TEXT,
+  created_at     TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_llm_calls_run_id ON llm_calls(run_id);
+CREATE INDEX IF NOT EXISTS idx_llm_calls_user_id_created ON llm_calls(user_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_llm_calls_created_at ON llm_calls(created_at);
+
+-- +goose StatementEnd
+
+
+-- +goose Down
+-- +goose StatementBegin
+
+DELETE FROM usage_events WHERE event_type = 'llm_call';
+DROP TABLE IF EXISTS llm_calls;
+
+-- +goose StatementEnd
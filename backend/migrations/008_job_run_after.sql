@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Earliest time a queued job may be claimed; failed attempts push it out
+-- with exponential backoff
+ALTER TABLE jobs
+  ADD COLUMN IF NOT EXISTS run_after TIMESTAMPTZ NOT NULL DEFAULT now();
+
+CREATE INDEX IF NOT EXISTS idx_jobs_status_run_after ON jobs(status, run_after);
+
+-- +goose StatementEnd
+
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_jobs_status_run_after;
+
+ALTER TABLE jobs
+  DROP COLUMN IF EXISTS run_after;
+
+-- +goose StatementEnd
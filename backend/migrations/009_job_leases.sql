@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- A running job is leased to the worker in locked_by until
+-- lease_expires_at; the worker heartbeats to extend it and the reaper
+-- requeues jobs whose lease expired
+ALTER TABLE jobs
+  ADD COLUMN IF NOT EXISTS heartbeat_at TIMESTAMPTZ,
+  ADD COLUMN IF NOT EXISTS lease_expires_at TIMESTAMPTZ;
+
+-- Jobs claimed before leases existed keep the old 15 minute visibility timeout
+UPDATE jobs
+SET lease_expires_at = COALESCE(locked_at, updated_at) + interval '15 minutes'
+WHERE status = 'running' AND lease_expires_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_jobs_running_lease ON jobs(lease_expires_at) WHERE status = 'running';
+
+-- +goose StatementEnd
+
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_jobs_running_lease;
+
+ALTER TABLE jobs
+  DROP COLUMN IF EXISTS lease_expires_at,
+  DROP COLUMN IF EXISTS heartbeat_at;
+
+-- +goose StatementEnd
@@ -0,0 +1,45 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Job types are registered in code, so the type column becomes free text.
+-- Jobs carry their input in payload; run_id is only set for jobs that
+-- process a run.
+ALTER TABLE jobs
+  ALTER COLUMN type TYPE TEXT USING type::text,
+  ALTER COLUMN run_id DROP NOT NULL,
+  ADD COLUMN IF NOT EXISTS payload JSONB NOT NULL DEFAULT '{}'::jsonb;
+
+DROP TYPE IF EXISTS job_type;
+
+UPDATE jobs
+SET payload = jsonb_build_object('run_id', run_id)
+WHERE type = 'process_run' AND run_id IS NOT NULL;
+
+-- Claims filter by type, status and run_after
+DROP INDEX IF EXISTS idx_jobs_status_run_after;
+CREATE INDEX IF NOT EXISTS idx_jobs_type_status_run_after ON jobs(type, status, run_after);
+
+-- +goose StatementEnd
+
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_jobs_type_status_run_after;
+CREATE INDEX IF NOT EXISTS idx_jobs_status_run_after ON jobs(status, run_after);
+
+-- Only process_run jobs fit the old schema
+DELETE FROM jobs WHERE type <> 'process_run' OR run_id IS NULL;
+
+DO $$ BEGIN
+  CREATE TYPE job_type AS ENUM ('process_run');
+EXCEPTION
+  WHEN duplicate_object THEN NULL;
+END $$;
+
+ALTER TABLE jobs
+  DROP COLUMN IF EXISTS payload,
+  ALTER COLUMN run_id SET NOT NULL,
+  ALTER COLUMN type TYPE job_type USING type::job_type;
+
+-- +goose StatementEnd
@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- One row per attempt at a job: who ran it, for how long and how it ended
+-- (succeeded, failed, or expired when the worker's lease ran out)
+CREATE TABLE IF NOT EXISTS job_attempts (
+  id          UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  job_id      UUID NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+  attempt     INT NOT NULL,
+  worker_id   TEXT NOT NULL,
+  outcome     TEXT NOT NULL,
+  error       TEXT,
+  started_at  TIMESTAMPTZ NOT NULL,
+  finished_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_job_attempts_job_started ON job_attempts(job_id, started_at);
+
+-- Dead-letter listing
+CREATE INDEX IF NOT EXISTS idx_jobs_failed_updated ON jobs(updated_at) WHERE status = 'failed';
+
+-- +goose StatementEnd
+
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_jobs_failed_updated;
+DROP TABLE IF EXISTS job_attempts;
+
+-- +goose StatementEnd
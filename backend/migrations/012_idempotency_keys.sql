@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Idempotency-Key header values per user. A row is locked (locked_at set,
+-- completed_at NULL) while the first request runs and then holds the
+-- response that is replayed to retries with the same key and body.
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+  user_id       UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  key           TEXT NOT NULL,
+  method        TEXT NOT NULL,
+  path          TEXT NOT NULL,
+  request_hash  TEXT NOT NULL,
+  status_code   INT,
+  content_type  TEXT,
+  response_body BYTEA,
+  locked_at     TIMESTAMPTZ NOT NULL DEFAULT now(),
+  completed_at  TIMESTAMPTZ,
+  created_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+  expires_at    TIMESTAMPTZ NOT NULL,
+  PRIMARY KEY (user_id, key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys(expires_at);
+
+-- +goose StatementEnd
+
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS idempotency_keys;
+
+-- +goose StatementEnd